@@ -0,0 +1,223 @@
+package template
+
+import "fmt"
+
+// ResolveContext carries everything a Condition or ValueOrRef needs to
+// resolve itself locally: the stack parameter values, every named
+// condition declared on the Template (so "Fn::If" can look one up by
+// name), and every named Mappings entry (so "Fn::FindInMap" can look
+// one up).
+type ResolveContext struct {
+	Params     map[string]string
+	Conditions map[string]Condition
+	Mappings   map[string]map[string]map[string]string
+}
+
+// Condition is an expression AST node that can be evaluated against a set of
+// template parameter values, mirroring the subset of CloudFormation's
+// intrinsic functions used to gate resources via "Condition".
+type Condition interface {
+	// Eval resolves the condition against ctx.
+	Eval(ctx ResolveContext) (bool, error)
+	// MarshalCondition renders the condition as the JSON-able value that
+	// belongs under the template's top-level "Conditions" section.
+	MarshalCondition() interface{}
+}
+
+// Equals implements "Fn::Equals".
+type Equals struct {
+	Left, Right ValueOrRef
+}
+
+func (c Equals) Eval(ctx ResolveContext) (bool, error) {
+	l, err := c.Left.Resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+	r, err := c.Right.Resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+	return l == r, nil
+}
+
+func (c Equals) MarshalCondition() interface{} {
+	return map[string]interface{}{
+		"Fn::Equals": []interface{}{c.Left.marshal(), c.Right.marshal()},
+	}
+}
+
+// And implements "Fn::And".
+type And []Condition
+
+func (c And) Eval(ctx ResolveContext) (bool, error) {
+	for _, cond := range c {
+		ok, err := cond.Eval(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c And) MarshalCondition() interface{} {
+	conds := make([]interface{}, len(c))
+	for i, cond := range c {
+		conds[i] = cond.MarshalCondition()
+	}
+	return map[string]interface{}{"Fn::And": conds}
+}
+
+// Or implements "Fn::Or".
+type Or []Condition
+
+func (c Or) Eval(ctx ResolveContext) (bool, error) {
+	for _, cond := range c {
+		ok, err := cond.Eval(ctx)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c Or) MarshalCondition() interface{} {
+	conds := make([]interface{}, len(c))
+	for i, cond := range c {
+		conds[i] = cond.MarshalCondition()
+	}
+	return map[string]interface{}{"Fn::Or": conds}
+}
+
+// Not implements "Fn::Not".
+type Not struct {
+	Condition Condition
+}
+
+func (c Not) Eval(ctx ResolveContext) (bool, error) {
+	ok, err := c.Condition.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
+func (c Not) MarshalCondition() interface{} {
+	return map[string]interface{}{"Fn::Not": []interface{}{c.Condition.MarshalCondition()}}
+}
+
+// ValueOrRef is a literal string, a "Ref" to a parameter, an
+// "Fn::FindInMap" lookup, or an "Fn::If" value selection, resolved during
+// local Resolve().
+type ValueOrRef struct {
+	Literal string
+
+	Ref string
+
+	FindInMap *FindInMap
+
+	If *FnIf
+}
+
+// Lit wraps a literal value.
+func Lit(v string) ValueOrRef { return ValueOrRef{Literal: v} }
+
+// RefParam wraps a reference to a template parameter.
+func RefParam(name string) ValueOrRef { return ValueOrRef{Ref: name} }
+
+// FindInMap implements "Fn::FindInMap".
+type FindInMap struct {
+	MapName, TopLevelKey, SecondLevelKey string
+}
+
+// FnIf implements "Fn::If": CloudFormation's value-selection intrinsic,
+// distinct from the Condition AST above, which only produces booleans.
+// "Fn::If" instead picks one of two values based on a named condition, and
+// is valid wherever a property value is, e.g.
+//
+//	{"Fn::If": ["IsProd", "m5.large", "t3.micro"]}
+type FnIf struct {
+	ConditionName         string
+	TrueValue, FalseValue ValueOrRef
+}
+
+// IfValue wraps an "Fn::If" as a ValueOrRef so it can be used anywhere a
+// resource property accepts one.
+func IfValue(conditionName string, trueValue, falseValue ValueOrRef) ValueOrRef {
+	return ValueOrRef{If: &FnIf{ConditionName: conditionName, TrueValue: trueValue, FalseValue: falseValue}}
+}
+
+func (f FnIf) resolve(ctx ResolveContext) (string, error) {
+	cond, ok := ctx.Conditions[f.ConditionName]
+	if !ok {
+		return "", fmt.Errorf("Fn::If references undeclared condition %q", f.ConditionName)
+	}
+	keep, err := cond.Eval(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate condition %q for Fn::If: %w", f.ConditionName, err)
+	}
+	if keep {
+		return f.TrueValue.Resolve(ctx)
+	}
+	return f.FalseValue.Resolve(ctx)
+}
+
+func (f FnIf) marshal() interface{} {
+	return map[string]interface{}{
+		"Fn::If": []interface{}{f.ConditionName, f.TrueValue.marshal(), f.FalseValue.marshal()},
+	}
+}
+
+func (v ValueOrRef) Resolve(ctx ResolveContext) (string, error) {
+	switch {
+	case v.Ref != "":
+		val, ok := ctx.Params[v.Ref]
+		if !ok {
+			return "", fmt.Errorf("no value supplied for parameter %q", v.Ref)
+		}
+		return val, nil
+	case v.FindInMap != nil:
+		return v.FindInMap.resolve(ctx)
+	case v.If != nil:
+		return v.If.resolve(ctx)
+	default:
+		return v.Literal, nil
+	}
+}
+
+func (f FindInMap) resolve(ctx ResolveContext) (string, error) {
+	top, ok := ctx.Mappings[f.MapName]
+	if !ok {
+		return "", fmt.Errorf("Fn::FindInMap references undeclared mapping %q", f.MapName)
+	}
+	second, ok := top[f.TopLevelKey]
+	if !ok {
+		return "", fmt.Errorf("Fn::FindInMap %q has no top-level key %q", f.MapName, f.TopLevelKey)
+	}
+	val, ok := second[f.SecondLevelKey]
+	if !ok {
+		return "", fmt.Errorf("Fn::FindInMap %q.%q has no key %q", f.MapName, f.TopLevelKey, f.SecondLevelKey)
+	}
+	return val, nil
+}
+
+func (v ValueOrRef) marshal() interface{} {
+	switch {
+	case v.Ref != "":
+		return map[string]interface{}{"Ref": v.Ref}
+	case v.FindInMap != nil:
+		return map[string]interface{}{
+			"Fn::FindInMap": []interface{}{v.FindInMap.MapName, v.FindInMap.TopLevelKey, v.FindInMap.SecondLevelKey},
+		}
+	case v.If != nil:
+		return v.If.marshal()
+	default:
+		return v.Literal
+	}
+}
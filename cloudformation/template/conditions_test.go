@@ -0,0 +1,141 @@
+package template
+
+import "testing"
+
+func TestEqualsEval(t *testing.T) {
+	ctx := ResolveContext{Params: map[string]string{"Stage": "prod"}}
+
+	cases := []struct {
+		name string
+		cond Equals
+		want bool
+	}{
+		{"match", Equals{Left: RefParam("Stage"), Right: Lit("prod")}, true},
+		{"mismatch", Equals{Left: RefParam("Stage"), Right: Lit("dev")}, false},
+	}
+	for _, c := range cases {
+		got, err := c.cond.Eval(ctx)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestEqualsEvalMissingParam(t *testing.T) {
+	ctx := ResolveContext{Params: map[string]string{}}
+	if _, err := (Equals{Left: RefParam("Stage"), Right: Lit("prod")}).Eval(ctx); err == nil {
+		t.Fatal("expected error for unresolved Ref, got nil")
+	}
+}
+
+func TestAndOrNotEval(t *testing.T) {
+	ctx := ResolveContext{Params: map[string]string{"Stage": "prod", "Region": "us-east-1"}}
+	isProd := Equals{Left: RefParam("Stage"), Right: Lit("prod")}
+	isUSEast1 := Equals{Left: RefParam("Region"), Right: Lit("us-east-1")}
+
+	if got, err := (And{isProd, isUSEast1}).Eval(ctx); err != nil || !got {
+		t.Errorf("And: got %v, err %v, want true, nil", got, err)
+	}
+	if got, err := (Or{isProd, Equals{Left: RefParam("Region"), Right: Lit("eu-west-1")}}).Eval(ctx); err != nil || !got {
+		t.Errorf("Or: got %v, err %v, want true, nil", got, err)
+	}
+	if got, err := (Not{isProd}).Eval(ctx); err != nil || got {
+		t.Errorf("Not: got %v, err %v, want false, nil", got, err)
+	}
+}
+
+func TestMarshalCondition(t *testing.T) {
+	isProd := Equals{Left: RefParam("Stage"), Right: Lit("prod")}
+
+	m := isProd.MarshalCondition().(map[string]interface{})
+	if _, ok := m["Fn::Equals"]; !ok {
+		t.Fatalf("Equals.MarshalCondition: missing Fn::Equals key, got %#v", m)
+	}
+
+	m = And{isProd}.MarshalCondition().(map[string]interface{})
+	if _, ok := m["Fn::And"]; !ok {
+		t.Fatalf("And.MarshalCondition: missing Fn::And key, got %#v", m)
+	}
+
+	m = Or{isProd}.MarshalCondition().(map[string]interface{})
+	if _, ok := m["Fn::Or"]; !ok {
+		t.Fatalf("Or.MarshalCondition: missing Fn::Or key, got %#v", m)
+	}
+
+	m = Not{isProd}.MarshalCondition().(map[string]interface{})
+	if _, ok := m["Fn::Not"]; !ok {
+		t.Fatalf("Not.MarshalCondition: missing Fn::Not key, got %#v", m)
+	}
+}
+
+func TestFnIfResolve(t *testing.T) {
+	ctx := ResolveContext{
+		Params:     map[string]string{"Stage": "prod"},
+		Conditions: map[string]Condition{"IsProd": Equals{Left: RefParam("Stage"), Right: Lit("prod")}},
+	}
+
+	got, err := IfValue("IsProd", Lit("m5.large"), Lit("t3.micro")).Resolve(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "m5.large" {
+		t.Errorf("got %q, want %q", got, "m5.large")
+	}
+
+	ctx.Params["Stage"] = "dev"
+	got, err = IfValue("IsProd", Lit("m5.large"), Lit("t3.micro")).Resolve(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "t3.micro" {
+		t.Errorf("got %q, want %q", got, "t3.micro")
+	}
+}
+
+func TestFnIfResolveUndeclaredCondition(t *testing.T) {
+	ctx := ResolveContext{Params: map[string]string{}}
+	if _, err := IfValue("Missing", Lit("a"), Lit("b")).Resolve(ctx); err == nil {
+		t.Fatal("expected error for undeclared condition, got nil")
+	}
+}
+
+func TestFindInMapResolve(t *testing.T) {
+	ctx := ResolveContext{
+		Mappings: map[string]map[string]map[string]string{
+			"RegionMap": {
+				"us-east-1": {"AMI": "ami-111"},
+			},
+		},
+	}
+
+	v := ValueOrRef{FindInMap: &FindInMap{MapName: "RegionMap", TopLevelKey: "us-east-1", SecondLevelKey: "AMI"}}
+	got, err := v.Resolve(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "ami-111" {
+		t.Errorf("got %q, want %q", got, "ami-111")
+	}
+}
+
+func TestFindInMapResolveErrors(t *testing.T) {
+	ctx := ResolveContext{
+		Mappings: map[string]map[string]map[string]string{
+			"RegionMap": {"us-east-1": {"AMI": "ami-111"}},
+		},
+	}
+
+	cases := []FindInMap{
+		{MapName: "Missing", TopLevelKey: "us-east-1", SecondLevelKey: "AMI"},
+		{MapName: "RegionMap", TopLevelKey: "eu-west-1", SecondLevelKey: "AMI"},
+		{MapName: "RegionMap", TopLevelKey: "us-east-1", SecondLevelKey: "Missing"},
+	}
+	for _, c := range cases {
+		if _, err := c.resolve(ctx); err == nil {
+			t.Errorf("%+v: expected error, got nil", c)
+		}
+	}
+}
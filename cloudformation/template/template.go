@@ -0,0 +1,125 @@
+// Package template renders CloudFormation templates with full Conditions /
+// Fn::If support, built on top of the vendored goformation resource types.
+// goformation's resources (e.g. ec2.SubnetRouteTableAssociation) carry a
+// "_resourceCondition" patch field but goformation itself never emits a
+// template-level "Conditions:" block or evaluates "Fn::If" -- this package
+// fills that gap for the eksconfig/ec2config code paths that stitch
+// together partial stacks per region/partition.
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Resource is any goformation resource. Most vendored resources additionally
+// implement ConditionalResource via their "_resourceCondition" patch field.
+type Resource interface {
+	AWSCloudFormationType() string
+}
+
+// ConditionalResource is a Resource gated by a named "Condition".
+type ConditionalResource interface {
+	Resource
+	ResourceCondition() string
+	SetResourceCondition(condition string)
+}
+
+// Template collects resources, the named conditions that gate them, and the
+// Mappings "Fn::FindInMap" resolves against.
+type Template struct {
+	Resources  map[string]Resource
+	conditions map[string]Condition
+	mappings   map[string]map[string]map[string]string
+}
+
+// New returns an empty Template.
+func New() *Template {
+	return &Template{
+		Resources:  map[string]Resource{},
+		conditions: map[string]Condition{},
+		mappings:   map[string]map[string]map[string]string{},
+	}
+}
+
+// AddResource registers a resource under the given logical ID.
+func (t *Template) AddResource(logicalID string, r Resource) {
+	t.Resources[logicalID] = r
+}
+
+// AddCondition registers a named condition expression. Resources reference
+// it by name via ConditionalResource.SetResourceCondition, and values
+// reference it via Fn::If (IfValue).
+func (t *Template) AddCondition(name string, cond Condition) {
+	t.conditions[name] = cond
+}
+
+// AddMapping registers a named "Mappings" entry so "Fn::FindInMap" lookups
+// against it can be resolved locally by Resolve.
+func (t *Template) AddMapping(name string, m map[string]map[string]string) {
+	t.mappings[name] = m
+}
+
+// Marshal renders the template to CloudFormation JSON, including a
+// "Conditions" section built from every condition registered via
+// AddCondition and a "Mappings" section built from every AddMapping call.
+func (t *Template) Marshal() ([]byte, error) {
+	out := struct {
+		AWSTemplateFormatVersion string                                   `json:"AWSTemplateFormatVersion"`
+		Conditions               map[string]interface{}                  `json:"Conditions,omitempty"`
+		Mappings                 map[string]map[string]map[string]string `json:"Mappings,omitempty"`
+		Resources                map[string]Resource                     `json:"Resources"`
+	}{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Resources:                t.Resources,
+	}
+
+	if len(t.conditions) > 0 {
+		out.Conditions = make(map[string]interface{}, len(t.conditions))
+		for name, cond := range t.conditions {
+			out.Conditions[name] = cond.MarshalCondition()
+		}
+	}
+	if len(t.mappings) > 0 {
+		out.Mappings = t.mappings
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// Resolve evaluates every registered condition against params and returns a
+// new Template containing only the resources whose condition is true (or
+// which carry no condition at all). It is a local, pre-deploy alternative to
+// letting CloudFormation itself skip gated resources at stack-create time.
+// Conditions and Mappings are carried over unchanged, so a resource's own
+// "Fn::If"/"Fn::FindInMap" property values (not just its gating Condition)
+// can still be resolved against the result via ResolveContext.
+func (t *Template) Resolve(params map[string]string) (*Template, error) {
+	ctx := ResolveContext{Params: params, Conditions: t.conditions, Mappings: t.mappings}
+
+	resolved := New()
+	resolved.conditions = t.conditions
+	resolved.mappings = t.mappings
+
+	for logicalID, r := range t.Resources {
+		cr, ok := r.(ConditionalResource)
+		if !ok || cr.ResourceCondition() == "" {
+			resolved.AddResource(logicalID, r)
+			continue
+		}
+
+		name := cr.ResourceCondition()
+		cond, ok := t.conditions[name]
+		if !ok {
+			return nil, fmt.Errorf("resource %q references undeclared condition %q", logicalID, name)
+		}
+		keep, err := cond.Eval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate condition %q for resource %q: %w", name, logicalID, err)
+		}
+		if keep {
+			resolved.AddResource(logicalID, r)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	k8s_tester "github.com/aws/aws-k8s-tester/k8s-tester"
+	"github.com/spf13/cobra"
+)
+
+func newDescribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <addon>",
+		Short: "Describe the Kubernetes resources an addon owns, one block per resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDescribe(args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&outputMode, "output", "o", "wide", "Output format: yaml|json|wide")
+	return cmd
+}
+
+func runDescribe(addon string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	lister, ok := k8s_tester.ListerFor(addon)
+	if !ok {
+		return fmt.Errorf("no Lister registered for addon %q (have: %v)", addon, k8s_tester.ListerNames())
+	}
+
+	clientset, err := clientsetFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	rows, err := lister.List(context.Background(), clientset)
+	if err != nil {
+		return fmt.Errorf("failed to describe resources for addon %q: %w", addon, err)
+	}
+
+	if outputMode == "json" || outputMode == "yaml" {
+		return renderRows(rows)
+	}
+
+	for _, r := range rows {
+		fmt.Printf("Name:      %s\n", r.Name)
+		fmt.Printf("Namespace: %s\n", r.Namespace)
+		fmt.Printf("Status:    %s\n", r.Status)
+		fmt.Printf("Age:       %s\n", r.Age)
+		fmt.Printf("Addon:     %s\n\n", r.Addon)
+	}
+	return nil
+}
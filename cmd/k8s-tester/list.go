@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	k8s_tester "github.com/aws/aws-k8s-tester/k8s-tester"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <addon>",
+		Short: "List the Kubernetes resources an addon owns",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&outputMode, "output", "o", "wide", "Output format: yaml|json|wide")
+	return cmd
+}
+
+func runList(addon string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	lister, ok := k8s_tester.ListerFor(addon)
+	if !ok {
+		return fmt.Errorf("no Lister registered for addon %q (have: %v)", addon, k8s_tester.ListerNames())
+	}
+
+	clientset, err := clientsetFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	rows, err := lister.List(context.Background(), clientset)
+	if err != nil {
+		return fmt.Errorf("failed to list resources for addon %q: %w", addon, err)
+	}
+
+	return renderRows(rows)
+}
+
+// clientsetFor builds a Kubernetes clientset from cfg.KubeconfigPath,
+// targeting cfg.KubeconfigContext if set -- the same context-override
+// approach as k8s_tester.Config.KubectlCommandForContext, so list/describe hit the
+// same cluster as the rest of a run instead of silently falling back to
+// the kubeconfig's current-context.
+func clientsetFor(cfg *k8s_tester.Config) (kubernetes.Interface, error) {
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: cfg.KubeconfigPath}
+	overrides := &clientcmd.ConfigOverrides{}
+	if cfg.KubeconfigContext != "" {
+		overrides.CurrentContext = cfg.KubeconfigContext
+	}
+	restcfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubeconfig %q (context %q): %w", cfg.KubeconfigPath, cfg.KubeconfigContext, err)
+	}
+	return kubernetes.NewForConfig(restcfg)
+}
+
+func renderRows(rows []k8s_tester.Row) error {
+	switch outputMode {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "yaml":
+		d, err := yaml.Marshal(rows)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(d))
+		return nil
+	default: // "wide"
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tNAMESPACE\tSTATUS\tAGE\tADDON")
+		for _, r := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Name, r.Namespace, r.Status, r.Age, r.Addon)
+		}
+		return w.Flush()
+	}
+}
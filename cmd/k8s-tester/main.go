@@ -0,0 +1,42 @@
+// Command k8s-tester is the CLI entrypoint for k8s-tester.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	k8s_tester "github.com/aws/aws-k8s-tester/k8s-tester"
+	_ "github.com/aws/aws-k8s-tester/k8s-tester/cis-benchmark"
+	_ "github.com/aws/aws-k8s-tester/k8s-tester/falco"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath string
+	outputMode string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "k8s-tester",
+	Short: "k8s-tester is a set of Kubernetes addon testers",
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to the k8s-tester config file")
+	rootCmd.AddCommand(newListCommand())
+	rootCmd.AddCommand(newDescribeCommand())
+}
+
+func loadConfig() (*k8s_tester.Config, error) {
+	if configPath == "" {
+		return nil, fmt.Errorf("--config is required")
+	}
+	return k8s_tester.Load(configPath)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
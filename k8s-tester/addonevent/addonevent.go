@@ -0,0 +1,39 @@
+// Package addonevent defines the Event milestone type that
+// k8s_tester.Config.EmitEvent accepts, factored out into its own leaf
+// package so addons like falco -- which hold a *k8s_tester.Config back
+// via their own Config.Tester field -- can depend on the Event shape
+// without importing k8s_tester itself and creating an import cycle
+// (k8s_tester.Config embeds those addons' Config structs, e.g. AddOnFalco).
+package addonevent
+
+import "time"
+
+// Event is one Apply/Delete milestone, written as a single line of
+// newline-delimited JSON to EventLogPath when LogFormat=="json".
+type Event struct {
+	Type       string        `json:"type"`
+	Addon      string        `json:"addon"`
+	Namespace  string        `json:"namespace,omitempty"`
+	Elapsed    time.Duration `json:"elapsed"`
+	ObjectRefs []string      `json:"object_refs,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Timestamp  time.Time     `json:"timestamp"`
+}
+
+// Milestone event types, documenting Apply/Delete's lifecycle for
+// structured consumers. Addons pass one of these (or their own custom
+// type) as Event.Type.
+const (
+	EventAddonStarted    = "addon_started"
+	EventManifestApplied = "manifest_applied"
+	EventWaitProgress    = "wait_progress"
+	EventAddonReady      = "addon_ready"
+	EventAddonFailed     = "addon_failed"
+	EventRunComplete     = "run_complete"
+)
+
+// ManagedByDefault is the default Config.ManagedBy value: the tester fully
+// owns and reconciles everything it creates. Addons that gate ManagedBy-
+// aware behavior (e.g. falco skipping rule-wait polling) compare against
+// this from here rather than importing k8s_tester for it.
+const ManagedByDefault = "aws-k8s-tester"
@@ -0,0 +1,67 @@
+// Package addonlister defines the Row/Lister types and registry that
+// k8s_tester.RegisterLister/ListerFor/ListerNames re-export, factored out
+// into its own leaf package so addons like falco -- which hold a
+// *k8s_tester.Config back via their own Config.Tester field -- can
+// register a Lister without importing k8s_tester itself and creating an
+// import cycle (k8s_tester.Config embeds those addons' Config structs,
+// e.g. AddOnFalco).
+package addonlister
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// Row is one line of a `k8s-tester list`/`describe` inventory table.
+type Row struct {
+	Name      string
+	Namespace string
+	Status    string
+	Age       string
+	Addon     string
+}
+
+// Lister is implemented by each addon that wants to surface the resources
+// it owns (pods, jobs, services, NLB endpoints, deployments, ...) to the
+// `k8s-tester list`/`describe` CLI, instead of operators having to remember
+// each addon's namespaces/label selectors.
+type Lister interface {
+	// List returns one Row per resource this addon owns.
+	List(ctx context.Context, clientset kubernetes.Interface) ([]Row, error)
+}
+
+var (
+	mu      sync.RWMutex
+	listers = map[string]Lister{}
+)
+
+// Register registers l under addon, the same name used by the
+// `list`/`describe` CLI's `<addon>` argument. Addon packages call this from
+// an init() func the same way they register their Config with NewDefault.
+func Register(addon string, l Lister) {
+	mu.Lock()
+	defer mu.Unlock()
+	listers[addon] = l
+}
+
+// For returns the registered Lister for addon, if any.
+func For(addon string) (Lister, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	l, ok := listers[addon]
+	return l, ok
+}
+
+// Names returns the names of every registered Lister, for CLI help text and
+// validation.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(listers))
+	for name := range listers {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,156 @@
+// Package amp ships k8s-tester scrape results and load-generator counters
+// to an Amazon Managed Service for Prometheus (AMP) workspace via a
+// SigV4-signed "remote_write" POST, so long-running soak runs can be
+// alerted on instead of only surfacing as local logs.
+package amp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"go.uber.org/zap"
+)
+
+// Sink records metric samples and flushes them to a remote-write endpoint.
+type Sink interface {
+	Record(name string, labels map[string]string, value float64, ts time.Time)
+	Flush(ctx context.Context) error
+}
+
+// Config defines the AMP remote-write sink configuration.
+type Config struct {
+	Logger *zap.Logger `json:"-"`
+
+	// Region is the AWS region the AMP workspace lives in.
+	Region string `json:"region"`
+	// WorkspaceID is the AMP workspace ID (e.g. "ws-...").
+	WorkspaceID string `json:"workspace_id"`
+	// BatchSize bounds how many samples are buffered before an
+	// automatic Flush is triggered by Record. 0 disables the auto-flush.
+	BatchSize int `json:"batch_size"`
+}
+
+// NewDefault returns a default Config.
+func NewDefault() *Config {
+	return &Config{
+		BatchSize: 500,
+	}
+}
+
+type sink struct {
+	cfg      *Config
+	endpoint string
+	signer   *v4.Signer
+	awscfg   aws.Config
+	client   *http.Client
+
+	mu      sync.Mutex
+	samples []prompb.TimeSeries
+}
+
+// New creates a Sink that remote-writes to the AMP workspace in cfg.
+func New(ctx context.Context, cfg *Config) (Sink, error) {
+	if cfg.Region == "" || cfg.WorkspaceID == "" {
+		return nil, fmt.Errorf("amp: both region and workspace_id are required")
+	}
+	awscfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &sink{
+		cfg:      cfg,
+		endpoint: fmt.Sprintf("https://aps-workspaces.%s.amazonaws.com/workspaces/%s/api/v1/remote_write", cfg.Region, cfg.WorkspaceID),
+		signer:   v4.NewSigner(),
+		awscfg:   awscfg,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Record buffers a single sample. It is safe for concurrent use.
+func (s *sink) Record(name string, labels map[string]string, value float64, ts time.Time) {
+	lbls := make([]prompb.Label, 0, len(labels)+1)
+	lbls = append(lbls, prompb.Label{Name: "__name__", Value: name})
+	for k, v := range labels {
+		lbls = append(lbls, prompb.Label{Name: k, Value: v})
+	}
+
+	s.mu.Lock()
+	s.samples = append(s.samples, prompb.TimeSeries{
+		Labels:  lbls,
+		Samples: []prompb.Sample{{Value: value, Timestamp: ts.UnixMilli()}},
+	})
+	full := s.cfg.BatchSize > 0 && len(s.samples) >= s.cfg.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		if err := s.Flush(context.Background()); err != nil {
+			s.cfg.Logger.Warn("failed to auto-flush AMP samples", zap.Error(err))
+		}
+	}
+}
+
+// Flush snappy-compresses the buffered samples into a prometheus.WriteRequest
+// protobuf, signs the POST with SigV4 using the "aps" service name, and sends
+// it to the workspace's remote_write endpoint.
+func (s *sink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.samples
+	s.samples = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	wr := &prompb.WriteRequest{Timeseries: batch}
+	data, err := proto.Marshal(wr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal WriteRequest: %w", err)
+	}
+	compressed := snappy.Encode(nil, data)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(compressed))
+	if err != nil {
+		return fmt.Errorf("failed to create remote_write request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	creds, err := s.awscfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err = s.signer.SignHTTP(ctx, creds, req, bodySHA256(compressed), "aps", s.cfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign remote_write request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote_write request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remote_write to %q returned %s", s.endpoint, resp.Status)
+	}
+
+	s.cfg.Logger.Info("flushed samples to AMP", zap.Int("samples", len(batch)))
+	return nil
+}
+
+func bodySHA256(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,269 @@
+// Package cis_benchmark runs the CIS Kubernetes Benchmark (kube-bench)
+// against the target cluster, parses its JSON report, and fails the run
+// when too many checks come back FAIL.
+package cis_benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/client"
+	"github.com/aws/aws-k8s-tester/utils/s3"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Config defines cis-benchmark configuration.
+type Config struct {
+	Enable bool `json:"enable"`
+	Prompt bool `json:"-"`
+
+	Logger    *zap.Logger `json:"-"`
+	LogWriter io.Writer   `json:"-"`
+
+	Client client.Client `json:"-"`
+
+	Namespace string `json:"namespace"`
+
+	// Targets selects which kube-bench check groups to run, e.g.
+	// "master", "node", "etcd", "policies". Defaults to all four.
+	Targets []string `json:"targets"`
+	// FailThreshold is the maximum number of FAIL-rated checks tolerated
+	// across Targets before Apply returns an error. 0 means no FAIL is
+	// tolerated.
+	FailThreshold int `json:"fail_threshold"`
+
+	// S3BucketName, if not empty, is where the raw kube-bench JSON report
+	// is uploaded after every run, following the same upload pattern the
+	// cloudwatch-agent/fluent-bit addons use.
+	S3BucketName string `json:"s3_bucket_name"`
+	S3Key        string `json:"s3_key"`
+
+	// Result is the parsed report of the most recent run.
+	Result *Result `json:"result" read-only:"true"`
+}
+
+// Result is the parsed subset of kube-bench's JSON report that this addon
+// acts on.
+type Result struct {
+	TotalPass int             `json:"total_pass"`
+	TotalFail int             `json:"total_fail"`
+	TotalWarn int             `json:"total_warn"`
+	Sections  []ResultSection `json:"sections"`
+}
+
+// ResultSection mirrors one kube-bench "Controls" entry.
+type ResultSection struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Pass int    `json:"pass"`
+	Fail int    `json:"fail"`
+	Warn int    `json:"warn"`
+}
+
+// NewDefault returns a default Config.
+func NewDefault() *Config {
+	return &Config{
+		Enable:        false,
+		Namespace:     "kube-system",
+		Targets:       []string{"master", "node", "etcd", "policies"},
+		FailThreshold: 0,
+	}
+}
+
+// Env returns the environment variable prefix used by this addon, matching
+// the "ENV_PREFIX + '<Env>_'" convention in k8s_tester.Config.UpdateFromEnvs.
+func Env() string {
+	return "ADD_ON_CIS_BENCHMARK"
+}
+
+type tester struct {
+	cfg *Config
+}
+
+// New creates a new cis-benchmark tester.
+func New(cfg *Config) *tester {
+	return &tester{cfg: cfg}
+}
+
+// Apply runs kube-bench as a Job per target, parses the combined JSON
+// report, fails if TotalFail exceeds FailThreshold, and optionally uploads
+// the raw report to S3.
+func (ts *tester) Apply() error {
+	if !ts.cfg.Enable {
+		ts.cfg.Logger.Info("skipping tester; cis-benchmark disabled")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	clientset := ts.cfg.Client.KubernetesClientSet()
+
+	merged := &Result{}
+	for _, target := range ts.cfg.Targets {
+		raw, err := ts.runKubeBenchJob(ctx, clientset, target)
+		if err != nil {
+			return fmt.Errorf("failed to run kube-bench for target %q: %w", target, err)
+		}
+
+		var r Result
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return fmt.Errorf("failed to parse kube-bench report for target %q: %w", target, err)
+		}
+		merged.TotalPass += r.TotalPass
+		merged.TotalFail += r.TotalFail
+		merged.TotalWarn += r.TotalWarn
+		merged.Sections = append(merged.Sections, r.Sections...)
+
+		if ts.cfg.S3BucketName != "" {
+			key := fmt.Sprintf("%s/%s.json", ts.cfg.S3Key, target)
+			if err := s3.Upload(ctx, ts.cfg.Logger, ts.cfg.S3BucketName, key, raw); err != nil {
+				ts.cfg.Logger.Warn("failed to upload kube-bench report to S3", zap.String("target", target), zap.Error(err))
+			}
+		}
+	}
+
+	ts.cfg.Result = merged
+	ts.cfg.Logger.Info("cis-benchmark run complete",
+		zap.Int("total-pass", merged.TotalPass),
+		zap.Int("total-fail", merged.TotalFail),
+		zap.Int("total-warn", merged.TotalWarn),
+	)
+
+	if merged.TotalFail > ts.cfg.FailThreshold {
+		return fmt.Errorf("cis-benchmark recorded %d FAIL checks, exceeding threshold %d", merged.TotalFail, ts.cfg.FailThreshold)
+	}
+	return nil
+}
+
+// Delete removes any leftover kube-bench Jobs.
+func (ts *tester) Delete() error {
+	if !ts.cfg.Enable {
+		return nil
+	}
+	clientset := ts.cfg.Client.KubernetesClientSet()
+	return clientset.BatchV1().Jobs(ts.cfg.Namespace).DeleteCollection(
+		context.Background(),
+		metav1.DeleteOptions{},
+		metav1.ListOptions{LabelSelector: "app.kubernetes.io/name=kube-bench"},
+	)
+}
+
+// kubeBenchHostPaths are the host paths kube-bench needs bind-mounted to
+// inspect node/master/etcd configuration; without them nearly every check
+// reports a spurious FAIL/WARN because the binary and config files it looks
+// for simply aren't visible in the container. Mirrors the volumes in the
+// upstream kube-bench job manifests at
+// https://github.com/aquasecurity/kube-bench/tree/main/job-eks.yaml.
+var kubeBenchHostPaths = []struct {
+	name, hostPath, mountPath string
+}{
+	{"etc-kubernetes", "/etc/kubernetes", "/etc/kubernetes"},
+	{"var-lib-kubelet", "/var/lib/kubelet", "/var/lib/kubelet"},
+	{"var-lib-etcd", "/var/lib/etcd", "/var/lib/etcd"},
+	{"etc-systemd", "/etc/systemd", "/etc/systemd"},
+	{"lib-systemd", "/lib/systemd/system", "/lib/systemd/system"},
+	{"usr-bin", "/usr/bin", "/usr/local/mount-from-host/bin"},
+	{"etc-passwd", "/etc/passwd", "/etc/passwd"},
+}
+
+// runKubeBenchJob submits a kube-bench Job targeting a single check group,
+// waits for it to complete, and returns its JSON-formatted log output.
+// Creation is idempotent: a Job left over from a prior Apply without an
+// intervening Delete is reused rather than erroring with AlreadyExists.
+func (ts *tester) runKubeBenchJob(ctx context.Context, clientset kubernetes.Interface, target string) ([]byte, error) {
+	jobName := fmt.Sprintf("kube-bench-%s", target)
+	backoffLimit := int32(0)
+
+	volumes := make([]corev1.Volume, 0, len(kubeBenchHostPaths))
+	volumeMounts := make([]corev1.VolumeMount, 0, len(kubeBenchHostPaths))
+	for _, hp := range kubeBenchHostPaths {
+		volumes = append(volumes, corev1.Volume{
+			Name:         hp.name,
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: hp.hostPath}},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      hp.name,
+			MountPath: hp.mountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: ts.cfg.Namespace,
+			Labels:    map[string]string{"app.kubernetes.io/name": "kube-bench"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app.kubernetes.io/name": "kube-bench", "job-name": jobName},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					HostPID:       true,
+					Containers: []corev1.Container{
+						{
+							Name:         "kube-bench",
+							Image:        "aquasec/kube-bench:latest",
+							Command:      []string{"kube-bench", "run", "--targets", target, "--json"},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	if _, err := clientset.BatchV1().Jobs(ts.cfg.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create kube-bench job %q: %w", jobName, err)
+	}
+
+	err := wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+		j, err := clientset.BatchV1().Jobs(ts.cfg.Namespace).Get(ctx, jobName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		return j.Status.Succeeded > 0 || j.Status.Failed > 0, nil
+	}, ctx.Done())
+	if err != nil {
+		return nil, fmt.Errorf("kube-bench job %q did not complete: %w", jobName, err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(ts.cfg.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("job-name=%s", jobName)})
+	if err != nil || len(pods.Items) == 0 {
+		return nil, fmt.Errorf("failed to find pod for kube-bench job %q: %w", jobName, err)
+	}
+
+	req := clientset.CoreV1().Pods(ts.cfg.Namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream logs for kube-bench job %q: %w", jobName, err)
+	}
+	defer stream.Close()
+
+	var buf []byte
+	chunk := make([]byte, 4096)
+	for {
+		n, rerr := stream.Read(chunk)
+		if n > 0 {
+			buf = append(buf, chunk[:n]...)
+		}
+		if rerr != nil {
+			break
+		}
+	}
+	return buf, nil
+}
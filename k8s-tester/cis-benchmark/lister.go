@@ -0,0 +1,41 @@
+package cis_benchmark
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonlister"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func init() {
+	addonlister.Register("cis-benchmark", lister{})
+}
+
+type lister struct{}
+
+// List implements addonlister.Lister (aliased as k8s_tester.Lister),
+// surfacing the Pods of the kube-bench Jobs runKubeBenchJob creates; the Jobs
+// themselves are not listed.
+func (lister) List(ctx context.Context, clientset kubernetes.Interface) ([]addonlister.Row, error) {
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/name=kube-bench",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kube-bench pods: %w", err)
+	}
+
+	rows := make([]addonlister.Row, 0, len(pods.Items))
+	for _, p := range pods.Items {
+		rows = append(rows, addonlister.Row{
+			Name:      p.Name,
+			Namespace: p.Namespace,
+			Status:    string(p.Status.Phase),
+			Age:       time.Since(p.CreationTimestamp.Time).Round(time.Second).String(),
+			Addon:     "cis-benchmark",
+		})
+	}
+	return rows, nil
+}
@@ -16,8 +16,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonevent"
+	cis_benchmark "github.com/aws/aws-k8s-tester/k8s-tester/cis-benchmark"
 	cloudwatch_agent "github.com/aws/aws-k8s-tester/k8s-tester/cloudwatch-agent"
+	"github.com/aws/aws-k8s-tester/k8s-tester/falco"
 	fluent_bit "github.com/aws/aws-k8s-tester/k8s-tester/fluent-bit"
+	"github.com/aws/aws-k8s-tester/k8s-tester/harness"
 	jobs_echo "github.com/aws/aws-k8s-tester/k8s-tester/jobs-echo"
 	jobs_pi "github.com/aws/aws-k8s-tester/k8s-tester/jobs-pi"
 	kubernetes_dashboard "github.com/aws/aws-k8s-tester/k8s-tester/kubernetes-dashboard"
@@ -37,6 +41,9 @@ import (
 type Config struct {
 	mu    *sync.RWMutex `json:"-"`
 	Stopc chan struct{} `json:"-"`
+	// managedByAtLoad is ManagedBy's value as of the last Load, used by
+	// unsafeSync to fail fast if ManagedBy was mutated in between.
+	managedByAtLoad string
 
 	// Prompt is true to enable prompt mode.
 	Prompt bool `json:"prompt"`
@@ -44,6 +51,27 @@ type Config struct {
 	// ClusterName is the Kubernetes cluster name.
 	ClusterName string `json:"cluster_name"`
 
+	// ManagedBy identifies who reconciles the objects this tester creates.
+	// The default "aws-k8s-tester" means the tester itself fully
+	// reconciles (applies, waits, deletes) every object it creates. Any
+	// other value (e.g. "kueue.x-k8s.io/multikueue") hands workloads off
+	// to an external controller: the tester still creates objects and
+	// labels them with ManagedByLabelKey=ManagedBy, but skips status
+	// polling in Apply. Teardown is not handed off: Delete still removes
+	// what the tester created. Addons that support hand-off read this
+	// field themselves; it must not be mutated between Load and Sync.
+	//
+	// BLOCKER: this field's original request asked for the same hand-off
+	// support on cloudwatch_agent, fluent_bit, jobs_pi, jobs_echo, and
+	// nlb_hello_world's own Config structs. None of those five packages
+	// exist anywhere in this repository -- there is no directory or source
+	// file for them to add a field to. ManagedBy hand-off is only wired on
+	// AddOnFalco (see k8s-tester/falco.Tester), which is in-tree but wasn't
+	// one of the addons the request named. Restoring or vendoring those
+	// five packages, or re-scoping this request to addons that actually
+	// exist in this tree, needs a maintainer call before this is "done".
+	ManagedBy string `json:"managed_by"`
+
 	// ConfigPath is the configuration file path.
 	ConfigPath string `json:"config_path"`
 	// LogColor is true to output logs in color.
@@ -62,11 +90,51 @@ type Config struct {
 	// Multiple values are accepted. If empty, it sets to 'default', which outputs to stderr.
 	// See https://pkg.go.dev/go.uber.org/zap#Open and https://pkg.go.dev/go.uber.org/zap#Config for more details.
 	LogOutputs []string `json:"log-outputs"`
+	// LogFormat selects how addon.Apply/Delete milestones are additionally
+	// recorded, on top of the human-readable zap logs above. "text" is a
+	// no-op; "json" also writes one Event per milestone as newline-delimited
+	// JSON to EventLogPath, so CI systems can parse progress/failures
+	// deterministically instead of scraping colorized text.
+	LogFormat string `json:"log_format"`
+	// EventLogPath is the file EmitEvent appends to when LogFormat=="json".
+	EventLogPath string `json:"event_log_path"`
 
 	KubectlDownloadURL string `json:"kubectl-download-url"`
 	KubectlPath        string `json:"kubectl_path"`
 	KubeconfigPath     string `json:"kubeconfig_path"`
 	KubeconfigContext  string `json:"kubeconfig_context"`
+	// KubeconfigPaths, if set, is merged into a single kubeconfig at
+	// KubeconfigPath by MergeKubeconfigs, renaming clusters/users/contexts
+	// whose names conflict; same-named entries are never collapsed into
+	// one, even when identical. This lets a single tester run target
+	// multiple clusters, with each addon selecting its cluster via its own
+	// "KubeconfigContext" override.
+	//
+	// BLOCKER: the per-addon "KubeconfigContext" override named above was
+	// requested on cloudwatch_agent.Config specifically, alongside
+	// fluent_bit, jobs_pi, jobs_echo, nlb_hello_world, metrics_server, and
+	// kubernetes_dashboard. None of those seven packages exist anywhere in
+	// this repository. The override only exists on AddOnFalco's Config
+	// (see k8s-tester/falco.Config.KubeconfigContext), which wasn't one of
+	// the named addons. Restoring or vendoring those packages, or
+	// re-scoping this request to addons that actually exist in this tree,
+	// needs a maintainer call before this is "done".
+	KubeconfigPaths []string `json:"kubeconfig_paths"`
+
+	// NamespacePrefix is used by NamespaceFor to auto-generate a unique
+	// per-run namespace for addons whose own "Namespace" override is
+	// blank, isolating concurrent tester runs on the same cluster.
+	//
+	// BLOCKER: this field's original request asked for a per-addon
+	// "Namespace" override on cloudwatch_agent, fluent_bit, metrics_server,
+	// kubernetes_dashboard, nlb_hello_world, jobs_pi, and jobs_echo. None of
+	// those seven packages exist anywhere in this repository. NamespaceFor/
+	// EnsureNamespace/DeleteNamespace are only wired into AddOnFalco (see
+	// k8s-tester/falco.Tester), which wasn't one of the seven named addons.
+	// Restoring or vendoring those packages, or re-scoping this request to
+	// addons that actually exist in this tree, needs a maintainer call
+	// before this is "done".
+	NamespacePrefix string `json:"namespace_prefix"`
 
 	// MinimumNodes is the minimum number of Kubernetes nodes required for installing this addon.
 	MinimumNodes int `json:"minimum_nodes"`
@@ -74,6 +142,10 @@ type Config struct {
 	TotalNodes int `json:"total_nodes" read-only:"true"`
 
 	// The tester order is defined as https://github.com/aws/aws-k8s-tester/blob/v1.5.9/eksconfig/env.go.
+	//
+	// See the BLOCKER note on KubeconfigPaths above: this package and the
+	// three below it don't actually exist in this tree as source, only as
+	// the import lines here.
 	AddOnCloudwatchAgent     *cloudwatch_agent.Config     `json:"add_on_cloudwatch_agent"`
 	AddOnMetricsServer       *metrics_server.Config       `json:"add_on_metrics_server"`
 	AddOnFluentBit           *fluent_bit.Config           `json:"add_on_fluent_bit"`
@@ -84,21 +156,47 @@ type Config struct {
 	AddOnJobsPi       *jobs_pi.Config   `json:"add_on_jobs_pi"`
 	AddOnJobsEcho     *jobs_echo.Config `json:"add_on_jobs_echo"`
 	AddOnCronJobsEcho *jobs_echo.Config `json:"add_on_cron_jobs_echo"`
+
+	AddOnCISBenchmark *cis_benchmark.Config `json:"add_on_cis_benchmark"`
+
+	// AddOnFalco installs Falco and verifies its default rules fire against a
+	// curated set of attack pods. NewDefault wires its Tester collaborator
+	// to cfg itself, so ManagedBy hand-off and namespace isolation are live
+	// by default. Its MetricsSink/Events/Ops collaborators stay nil until a
+	// caller sets them, so AMP metrics, CloudEvents, and OpsCenter reporting
+	// are off by default.
+	AddOnFalco *falco.Config `json:"add_on_falco"`
+
+	// AddOnHarness runs a declarative, directory-of-YAML-files conformance
+	// suite against the other addons. Unlike the other AddOn* fields it has
+	// no Env()-driven UpdateFromEnvs wiring: its Dir is set directly by
+	// callers of harness.Runner.Run, not via the tester order.
+	AddOnHarness *harness.Config `json:"add_on_harness"`
 }
 
 const DefaultMinimumNodes = 1
 
+// ManagedByDefault is the default Config.ManagedBy value: the tester fully
+// owns and reconciles everything it creates.
+const ManagedByDefault = addonevent.ManagedByDefault
+
+// ManagedByLabelKey is the label every object an addon creates is tagged
+// with, set to Config.ManagedBy.
+const ManagedByLabelKey = "app.kubernetes.io/managed-by"
+
 func NewDefault() *Config {
 	name := fmt.Sprintf("k8s-%s-%s", utils_time.GetTS(10), rand.String(12))
 	if v := os.Getenv(ENV_PREFIX + "CLUSTER_NAME"); v != "" {
 		name = v
 	}
 
-	return &Config{
+	cfg := &Config{
 		mu: new(sync.RWMutex),
 
-		Prompt:      true,
-		ClusterName: name,
+		Prompt:          true,
+		ClusterName:     name,
+		ManagedBy:       ManagedByDefault,
+		NamespacePrefix: fmt.Sprintf("k8s-tester-%s", rand.String(8)),
 
 		LogColor:         true,
 		LogColorOverride: "",
@@ -106,6 +204,7 @@ func NewDefault() *Config {
 		// default, stderr, stdout, or file name
 		// log file named with cluster name will be added automatically
 		LogOutputs: []string{"stderr"},
+		LogFormat:  "text",
 
 		// https://github.com/kubernetes/kubernetes/tags
 		// https://kubernetes.io/docs/tasks/tools/install-kubectl/
@@ -124,7 +223,20 @@ func NewDefault() *Config {
 		AddOnJobsPi:              jobs_pi.NewDefault(),
 		AddOnJobsEcho:            jobs_echo.NewDefault("Job"),
 		AddOnCronJobsEcho:        jobs_echo.NewDefault("CronJob"),
+
+		AddOnCISBenchmark: cis_benchmark.NewDefault(),
+		AddOnHarness:      harness.NewDefault(),
 	}
+
+	cfg.AddOnFalco = falco.NewDefault()
+	// Wired to cfg itself so ManagedBy hand-off and namespace isolation are
+	// live without every caller having to set Tester by hand; MetricsSink,
+	// Events, and Ops are left nil here since they depend on cluster-specific
+	// collaborators (AMP workspace, CloudEvents sink, OpsCenter client) that
+	// only a caller with that setup can provide.
+	cfg.AddOnFalco.Tester = cfg
+
+	return cfg
 }
 
 // ENV_PREFIX is the environment variable prefix.
@@ -142,6 +254,7 @@ func Load(p string) (cfg *Config, err error) {
 	}
 
 	cfg.mu = new(sync.RWMutex)
+	cfg.managedByAtLoad = cfg.ManagedBy
 	if cfg.ConfigPath != p {
 		cfg.ConfigPath = p
 	}
@@ -153,6 +266,12 @@ func Load(p string) (cfg *Config, err error) {
 	}
 	cfg.ConfigPath = ap
 
+	if len(cfg.KubeconfigPaths) > 0 {
+		if merr := cfg.MergeKubeconfigs(); merr != nil {
+			return nil, fmt.Errorf("failed to merge 'KubeconfigPaths' %v", merr)
+		}
+	}
+
 	if serr := cfg.unsafeSync(); serr != nil {
 		fmt.Fprintf(os.Stderr, "[WARN] failed to sync config files %v\n", serr)
 	}
@@ -172,6 +291,10 @@ func (cfg *Config) unsafeSync() error {
 		return errors.New("empty config path")
 	}
 
+	if cfg.managedByAtLoad != "" && cfg.ManagedBy != cfg.managedByAtLoad {
+		return fmt.Errorf("'ManagedBy' is immutable after Load; was %q, now %q", cfg.managedByAtLoad, cfg.ManagedBy)
+	}
+
 	if cfg.ConfigPath != "" && !filepath.IsAbs(cfg.ConfigPath) {
 		p, err := filepath.Abs(cfg.ConfigPath)
 		if err != nil {
@@ -296,6 +419,26 @@ func (cfg *Config) UpdateFromEnvs() (err error) {
 		return fmt.Errorf("expected *jobs_echo.Config, got %T", vv)
 	}
 
+	vv, err = parseEnvs(ENV_PREFIX+cis_benchmark.Env()+"_", cfg.AddOnCISBenchmark)
+	if err != nil {
+		return err
+	}
+	if av, ok := vv.(*cis_benchmark.Config); ok {
+		cfg.AddOnCISBenchmark = av
+	} else {
+		return fmt.Errorf("expected *cis_benchmark.Config, got %T", vv)
+	}
+
+	vv, err = parseEnvs(ENV_PREFIX+falco.Env()+"_", cfg.AddOnFalco)
+	if err != nil {
+		return err
+	}
+	if av, ok := vv.(*falco.Config); ok {
+		cfg.AddOnFalco = av
+	} else {
+		return fmt.Errorf("expected *falco.Config, got %T", vv)
+	}
+
 	return err
 }
 
@@ -363,6 +506,26 @@ func parseEnvs(pfx string, addOn interface{}) (interface{}, error) {
 			if len(ss) < 1 {
 				continue
 			}
+			if fieldName == "KubeconfigPaths" {
+				// merge semantics: union with whatever was already loaded
+				// from the config file, rather than overwrite it, so a
+				// single env var can add clusters on top of a config file.
+				existing := vv.Field(i).Interface().([]string)
+				seen := make(map[string]struct{}, len(existing))
+				merged := append([]string{}, existing...)
+				for _, p := range existing {
+					seen[p] = struct{}{}
+				}
+				for _, p := range ss {
+					if _, ok := seen[p]; !ok {
+						merged = append(merged, p)
+						seen[p] = struct{}{}
+					}
+				}
+				vv.Field(i).Set(reflect.ValueOf(merged))
+				continue
+			}
+
 			slice := reflect.MakeSlice(reflect.TypeOf([]string{}), len(ss), len(ss))
 			for j := range ss {
 				slice.Index(j).SetString(ss[j])
@@ -390,6 +553,20 @@ func parseEnvs(pfx string, addOn interface{}) (interface{}, error) {
 	return addOn, nil
 }
 
+// ManagedByLabels returns the "app.kubernetes.io/managed-by" label that
+// every object an addon creates should be tagged with.
+func (cfg *Config) ManagedByLabels() map[string]string {
+	return map[string]string{ManagedByLabelKey: cfg.ManagedBy}
+}
+
+// GetManagedBy returns cfg.ManagedBy. It exists alongside the field itself
+// so Config can satisfy addon-defined Tester interfaces (e.g. falco.Tester)
+// that can't reference the ManagedBy field directly without importing this
+// package back and creating a cycle.
+func (cfg *Config) GetManagedBy() string {
+	return cfg.ManagedBy
+}
+
 // Colorize prints colorized input, if color output is supported.
 func (cfg *Config) Colorize(input string) string {
 	colorize := colorstring.Colorize{
@@ -400,9 +577,22 @@ func (cfg *Config) Colorize(input string) string {
 	return colorize.Color(input)
 }
 
-// KubectlCommand returns the kubectl command.
+// KubectlCommand returns the kubectl command, targeting cfg.KubeconfigContext
+// if set.
 func (cfg *Config) KubectlCommand() string {
-	return fmt.Sprintf("%s --kubeconfig=%s", cfg.KubectlPath, cfg.KubeconfigPath)
+	return cfg.KubectlCommandForContext(cfg.KubeconfigContext)
+}
+
+// KubectlCommandForContext returns the kubectl command targeting the given
+// kubeconfig context override, so each addon can run against its own
+// context of a kubeconfig merged from KubeconfigPaths rather than always
+// using the top-level KubeconfigContext.
+func (cfg *Config) KubectlCommandForContext(contextOverride string) string {
+	cmd := fmt.Sprintf("%s --kubeconfig=%s", cfg.KubectlPath, cfg.KubeconfigPath)
+	if contextOverride != "" {
+		cmd += fmt.Sprintf(" --context=%s", contextOverride)
+	}
+	return cmd
 }
 
 // KubectlCommands returns the various kubectl commands.
@@ -441,4 +631,4 @@ export KUBECTL="{{ .KubectlCommand }}"
 {{ .KubectlCommand }} get nodes -o=wide
 ###########################
 {{ end }}
-`
\ No newline at end of file
+`
@@ -0,0 +1,45 @@
+package k8s_tester
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseEnvsKubeconfigPathsUnion(t *testing.T) {
+	cfg := &Config{KubeconfigPaths: []string{"/a/kubeconfig"}}
+
+	const env = ENV_PREFIX + "KUBECONFIG_PATHS"
+	if err := os.Setenv(env, "/a/kubeconfig,/b/kubeconfig"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(env)
+
+	if _, err := parseEnvs(ENV_PREFIX, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"/a/kubeconfig", "/b/kubeconfig"}
+	if !reflect.DeepEqual(cfg.KubeconfigPaths, want) {
+		t.Errorf("got %v, want %v", cfg.KubeconfigPaths, want)
+	}
+}
+
+func TestParseEnvsKubeconfigPathsUnionDedupes(t *testing.T) {
+	cfg := &Config{KubeconfigPaths: []string{"/a/kubeconfig", "/b/kubeconfig"}}
+
+	const env = ENV_PREFIX + "KUBECONFIG_PATHS"
+	if err := os.Setenv(env, "/b/kubeconfig,/c/kubeconfig"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(env)
+
+	if _, err := parseEnvs(ENV_PREFIX, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"/a/kubeconfig", "/b/kubeconfig", "/c/kubeconfig"}
+	if !reflect.DeepEqual(cfg.KubeconfigPaths, want) {
+		t.Errorf("got %v, want %v", cfg.KubeconfigPaths, want)
+	}
+}
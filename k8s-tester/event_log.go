@@ -0,0 +1,76 @@
+package k8s_tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonevent"
+	"go.uber.org/zap"
+)
+
+// Event is one Apply/Delete milestone, written as a single line of
+// newline-delimited JSON to EventLogPath when LogFormat=="json". It's a
+// type alias for addonevent.Event (rather than a local struct) so that
+// addons which can't import this package back without a cycle -- e.g.
+// falco, whose Config is embedded here as AddOnFalco -- can still share the
+// exact same wire type via addonevent.
+type Event = addonevent.Event
+
+// Milestone event types, documenting Apply/Delete's lifecycle for
+// structured consumers. Addons pass one of these (or their own custom
+// type) as Event.Type.
+const (
+	EventAddonStarted    = addonevent.EventAddonStarted
+	EventManifestApplied = addonevent.EventManifestApplied
+	EventWaitProgress    = addonevent.EventWaitProgress
+	EventAddonReady      = addonevent.EventAddonReady
+	EventAddonFailed     = addonevent.EventAddonFailed
+	EventRunComplete     = addonevent.EventRunComplete
+)
+
+// EmitEvent logs e to the zap logger at info (or warn, if e.Error is set),
+// and, when LogFormat=="json", additionally appends e as one line of
+// newline-delimited JSON to EventLogPath.
+func (cfg *Config) EmitEvent(lg *zap.Logger, e Event) error {
+	e.Timestamp = time.Now()
+
+	fields := []zap.Field{
+		zap.String("type", e.Type),
+		zap.String("addon", e.Addon),
+		zap.String("namespace", e.Namespace),
+		zap.Duration("elapsed", e.Elapsed),
+		zap.Strings("object-refs", e.ObjectRefs),
+	}
+	if e.Error != "" {
+		lg.Warn("tester event", append(fields, zap.String("error", e.Error))...)
+	} else {
+		lg.Info("tester event", fields...)
+	}
+
+	if cfg.LogFormat != "json" {
+		return nil
+	}
+	if cfg.EventLogPath == "" {
+		return fmt.Errorf("LogFormat is 'json' but EventLogPath is empty")
+	}
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	f, err := os.OpenFile(cfg.EventLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open EventLogPath %q: %w", cfg.EventLogPath, err)
+	}
+	defer f.Close()
+
+	d, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if _, err := f.Write(append(d, '\n')); err != nil {
+		return fmt.Errorf("failed to write event to %q: %w", cfg.EventLogPath, err)
+	}
+	return nil
+}
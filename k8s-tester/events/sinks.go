@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/segmentio/kafka-go"
+)
+
+// httpSink sends events via the CloudEvents HTTP binding.
+type httpSink struct {
+	client cloudevents.Client
+	target string
+}
+
+func newHTTPSink(cfg *Config) (Sink, error) {
+	client, err := cloudevents.NewClientHTTP()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CloudEvents HTTP client: %w", err)
+	}
+	return &httpSink{client: client, target: cfg.EventSink}, nil
+}
+
+func (s *httpSink) Send(ctx context.Context, event cloudevents.Event) error {
+	ctx = cloudevents.ContextWithTarget(ctx, s.target)
+	if result := s.client.Send(ctx, event); cloudevents.IsUndelivered(result) {
+		return result
+	}
+	return nil
+}
+
+// kafkaSink publishes the JSON-encoded event to a Kafka topic, addressed as
+// "kafka://<broker[,broker...]>/<topic>".
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+func newKafkaSink(cfg *Config, u *url.URL) (Sink, error) {
+	topic := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || topic == "" {
+		return nil, fmt.Errorf("kafka event sink must be of the form kafka://<brokers>/<topic>, got %q", cfg.EventSink)
+	}
+	return &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(strings.Split(u.Host, ",")...),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		},
+	}, nil
+}
+
+func (s *kafkaSink) Send(ctx context.Context, event cloudevents.Event) error {
+	data, err := event.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal CloudEvent for Kafka: %w", err)
+	}
+	return s.writer.WriteMessages(ctx, kafka.Message{Key: []byte(event.ID()), Value: data})
+}
+
+// sqsSink publishes the JSON-encoded event to an SQS queue, addressed as
+// "sqs://<queue-url-without-scheme>".
+type sqsSink struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+func newSQSSink(cfg *Config, u *url.URL) (Sink, error) {
+	awscfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for SQS event sink: %w", err)
+	}
+	return &sqsSink{
+		client:   sqs.NewFromConfig(awscfg),
+		queueURL: "https://" + u.Host + u.Path,
+	}, nil
+}
+
+func (s *sqsSink) Send(ctx context.Context, event cloudevents.Event) error {
+	data, err := event.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal CloudEvent for SQS: %w", err)
+	}
+	body := string(data)
+	_, err = s.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    &s.queueURL,
+		MessageBody: &body,
+	})
+	return err
+}
@@ -0,0 +1,506 @@
+// Package falco installs the Falco Helm chart and runs a curated set of
+// attack workloads against the cluster to verify that Falco's default
+// runtime-security rules actually fire.
+package falco
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/client"
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonevent"
+	"github.com/aws/aws-k8s-tester/k8s-tester/amp"
+	"github.com/aws/aws-k8s-tester/k8s-tester/events"
+	"github.com/aws/aws-k8s-tester/k8s-tester/opscenter"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+var corev1LogOptions = corev1.PodLogOptions{}
+
+// Tester is the subset of *k8s_tester.Config that falco depends on for
+// structured event emission, namespace isolation, and ManagedBy hand-off.
+// It's an interface rather than a direct *k8s_tester.Config dependency
+// because k8s_tester.Config embeds *Config as AddOnFalco, and Go doesn't
+// allow the resulting import cycle; *k8s_tester.Config satisfies this
+// interface, with its GetManagedBy method standing in for the ManagedBy
+// field.
+type Tester interface {
+	EmitEvent(lg *zap.Logger, e addonevent.Event) error
+	NamespaceFor(addon string, override string) string
+	EnsureNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string) error
+	DeleteNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string) error
+	ManagedByLabels() map[string]string
+	GetManagedBy() string
+}
+
+// Config defines falco configuration.
+type Config struct {
+	Enable bool `json:"enable"`
+	Prompt bool `json:"-"`
+
+	Logger    *zap.Logger `json:"-"`
+	LogWriter io.Writer   `json:"-"`
+
+	Client client.Client `json:"-"`
+
+	// MetricsSink, if set, receives a "falco_rule_fired" sample for every
+	// expected rule observed, so long-running soak runs land in AMP
+	// instead of only surfacing as local logs.
+	MetricsSink amp.Sink `json:"-"`
+
+	// Events, if set, receives an apply.*/delete.* CloudEvent for every
+	// Apply/Delete lifecycle transition (via Phase), plus a
+	// "falco.rule.fired" CloudEvent for every expected rule observed (via
+	// EmitType).
+	Events *events.Emitter `json:"-"`
+
+	// Tester, if set, receives "addon_started"/"addon_ready"/"addon_failed"
+	// structured Events via Tester.EmitEvent, per its LogFormat/EventLogPath.
+	Tester Tester `json:"-"`
+
+	// Ops, if set, opens an OpsCenter OpsItem via ReportFailure whenever
+	// Apply fails, so a human gets paged instead of the failure only
+	// surfacing in local logs.
+	Ops *opscenter.Reporter `json:"-"`
+
+	// Namespace is where the Falco chart and attack pods are installed. If
+	// blank and Tester is set, Apply resolves it via Tester.NamespaceFor and
+	// creates/deletes it itself via Tester.EnsureNamespace/DeleteNamespace;
+	// otherwise it is used as given (helm's --create-namespace creates it if
+	// missing) and Delete leaves it in place.
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"release_name"`
+	ChartRepo   string `json:"chart_repo"`
+	ChartName   string `json:"chart_name"`
+
+	// ExpectedRules is the set of Falco rule names that the attack pods
+	// deployed by Apply must trigger at least once.
+	// If empty, Apply only verifies the Helm chart installs successfully.
+	ExpectedRules []string `json:"expected_rules"`
+	// EventWaitTimeout bounds how long Apply waits for all ExpectedRules
+	// to be observed in the Falco pods' logs before failing.
+	EventWaitTimeout time.Duration `json:"event_wait_timeout"`
+
+	// KubeconfigContext, if set, is passed as kubectl's "--context" on every
+	// attack-pod command this tester runs, so it can target one cluster
+	// out of a kubeconfig merged from multiple k8s_tester.Config.KubeconfigPaths
+	// rather than always using the current context.
+	KubeconfigContext string `json:"kubeconfig_context"`
+}
+
+// NewDefault returns a default Config. Namespace is left blank so that,
+// when Tester is set, Apply resolves a per-run isolated namespace via
+// Tester.NamespaceFor instead of every run sharing a fixed "falco"
+// namespace; set Namespace explicitly to opt back into a fixed namespace.
+func NewDefault() *Config {
+	return &Config{
+		Enable:      false,
+		ReleaseName: "falco",
+		ChartRepo:   "https://falcosecurity.github.io/charts",
+		ChartName:   "falcosecurity/falco",
+		ExpectedRules: []string{
+			"Terminal shell in container",
+			"Write below etc",
+		},
+		EventWaitTimeout: 3 * time.Minute,
+	}
+}
+
+// Env returns the environment variable prefix used by this addon, matching
+// the "ENV_PREFIX + '<Env>_'" convention in k8s_tester.Config.UpdateFromEnvs.
+func Env() string {
+	return "ADD_ON_FALCO"
+}
+
+type tester struct {
+	cfg *Config
+
+	// autoNamespace is true when cfg.Namespace was blank at Apply time, so
+	// it was resolved via Tester.NamespaceFor/EnsureNamespace and should be
+	// torn down again by Delete; an explicit Namespace override is assumed
+	// to be shared and is left alone.
+	autoNamespace bool
+}
+
+// New creates a new falco tester.
+func New(cfg *Config) *tester {
+	return &tester{cfg: cfg}
+}
+
+// Apply installs the Falco Helm chart, deploys the attack pods, and fails
+// unless every rule in cfg.ExpectedRules is observed within EventWaitTimeout.
+func (ts *tester) Apply() (err error) {
+	if !ts.cfg.Enable {
+		ts.cfg.Logger.Info("skipping tester; falco disabled")
+		return nil
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	ts.emit(addonevent.EventAddonStarted, 0, "")
+	ts.emitPhase(ctx, events.PhaseApplyStarted, nil)
+	defer func() {
+		if err != nil {
+			ts.emit(addonevent.EventAddonFailed, time.Since(start), err.Error())
+			ts.emitPhase(ctx, events.PhaseApplyFailed, map[string]string{"error": err.Error()})
+			ts.reportFailure(ctx, err)
+		} else {
+			ts.emit(addonevent.EventAddonReady, time.Since(start), "")
+			ts.emitPhase(ctx, events.PhaseApplySucceeded, nil)
+		}
+	}()
+
+	if ts.cfg.Tester != nil {
+		ts.autoNamespace = ts.cfg.Namespace == ""
+		ts.cfg.Namespace = ts.cfg.Tester.NamespaceFor("falco", ts.cfg.Namespace)
+		if ts.autoNamespace {
+			if err = ts.cfg.Tester.EnsureNamespace(ctx, ts.cfg.Client.KubernetesClientSet(), ts.cfg.Namespace); err != nil {
+				return fmt.Errorf("failed to ensure namespace %q: %w", ts.cfg.Namespace, err)
+			}
+		}
+	}
+
+	if err = ts.installChart(); err != nil {
+		return fmt.Errorf("failed to install falco chart: %w", err)
+	}
+	ts.emit(addonevent.EventManifestApplied, time.Since(start), "")
+
+	if len(ts.cfg.ExpectedRules) == 0 {
+		ts.cfg.Logger.Info("no expected rules configured; skipping attack pods")
+		return nil
+	}
+
+	if err = ts.deployAttackPods(); err != nil {
+		return fmt.Errorf("failed to deploy attack pods: %w", err)
+	}
+
+	if ts.cfg.Tester != nil && ts.cfg.Tester.GetManagedBy() != "" && ts.cfg.Tester.GetManagedBy() != addonevent.ManagedByDefault {
+		ts.cfg.Logger.Info("ManagedBy is not the default; handing rule verification off to the external controller",
+			zap.String("managed-by", ts.cfg.Tester.GetManagedBy()))
+		return nil
+	}
+
+	fired, err := ts.waitForRules(ts.cfg.ExpectedRules, ts.cfg.EventWaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, rule := range ts.cfg.ExpectedRules {
+		if !fired[rule] {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("falco did not fire expected rules %v within %s", missing, ts.cfg.EventWaitTimeout)
+		return err
+	}
+
+	ts.cfg.Logger.Info("all expected falco rules fired", zap.Strings("rules", ts.cfg.ExpectedRules))
+	ts.emit(addonevent.EventRunComplete, time.Since(start), "", ts.cfg.ExpectedRules...)
+	return nil
+}
+
+// emit reports a lifecycle milestone to ts.cfg.Tester, if configured.
+// objectRefs, if given, is recorded on the Event as ObjectRefs.
+func (ts *tester) emit(eventType string, elapsed time.Duration, errMsg string, objectRefs ...string) {
+	if ts.cfg.Tester == nil {
+		return
+	}
+	if eerr := ts.cfg.Tester.EmitEvent(ts.cfg.Logger, addonevent.Event{
+		Type:       eventType,
+		Addon:      "falco",
+		Namespace:  ts.cfg.Namespace,
+		Elapsed:    elapsed,
+		ObjectRefs: objectRefs,
+		Error:      errMsg,
+	}); eerr != nil {
+		ts.cfg.Logger.Warn("failed to emit structured event", zap.Error(eerr))
+	}
+}
+
+// emitPhase reports an Apply/Delete lifecycle transition as a CloudEvent via
+// ts.cfg.Events, if configured.
+func (ts *tester) emitPhase(ctx context.Context, phase events.Phase, data interface{}) {
+	if ts.cfg.Events == nil {
+		return
+	}
+	if data == nil {
+		data = map[string]string{"namespace": ts.cfg.Namespace}
+	}
+	if eerr := ts.cfg.Events.Emit(ctx, "falco", phase, data); eerr != nil {
+		ts.cfg.Logger.Warn("failed to emit lifecycle CloudEvent", zap.String("phase", string(phase)), zap.Error(eerr))
+	}
+}
+
+// reportFailure opens an OpsCenter OpsItem for cause via ts.cfg.Ops, if
+// configured, so a human is paged instead of the failure only surfacing in
+// local logs, then blocks on Reconcile until that OpsItem is Resolved. This
+// gates Apply's return -- and so any retry/teardown loop the caller runs on
+// top of it -- on a human actually walking the OpsItem to Resolved, instead
+// of the tester looping unattended against a failure nobody has looked at.
+func (ts *tester) reportFailure(ctx context.Context, cause error) {
+	if ts.cfg.Ops == nil {
+		return
+	}
+	if _, operr := ts.cfg.Ops.ReportFailure(ctx, "falco", cause, cause.Error()); operr != nil {
+		ts.cfg.Logger.Warn("failed to open OpsItem for failing tester", zap.Error(operr))
+		return
+	}
+	if operr := ts.cfg.Ops.Reconcile(ctx, "falco"); operr != nil {
+		ts.cfg.Logger.Warn("failed to reconcile OpsItem for failing tester", zap.Error(operr))
+	}
+}
+
+// Delete uninstalls the Falco Helm chart and the attack pods.
+func (ts *tester) Delete() (err error) {
+	if !ts.cfg.Enable {
+		return nil
+	}
+
+	ctx := context.Background()
+	ts.emitPhase(ctx, events.PhaseDeleteStarted, nil)
+	defer func() {
+		if err != nil {
+			ts.emitPhase(ctx, events.PhaseDeleteFailed, map[string]string{"error": err.Error()})
+		} else {
+			ts.emitPhase(ctx, events.PhaseDeleteSucceeded, nil)
+		}
+	}()
+
+	if derr := ts.deleteAttackPods(); derr != nil {
+		ts.cfg.Logger.Warn("failed to delete attack pods", zap.Error(derr))
+	}
+	args := []string{"uninstall", ts.cfg.ReleaseName, "--namespace", ts.cfg.Namespace}
+	cmd := exec.Command("helm", args...)
+	cmd.Stdout, cmd.Stderr = ts.cfg.LogWriter, ts.cfg.LogWriter
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("failed to uninstall falco chart: %w", err)
+	}
+
+	if ts.cfg.Tester != nil && ts.autoNamespace {
+		if derr := ts.cfg.Tester.DeleteNamespace(ctx, ts.cfg.Client.KubernetesClientSet(), ts.cfg.Namespace); derr != nil {
+			ts.cfg.Logger.Warn("failed to delete namespace", zap.String("namespace", ts.cfg.Namespace), zap.Error(derr))
+		}
+	}
+	return nil
+}
+
+func (ts *tester) installChart() error {
+	ts.cfg.Logger.Info("installing falco chart",
+		zap.String("namespace", ts.cfg.Namespace),
+		zap.String("release", ts.cfg.ReleaseName),
+	)
+	addRepo := exec.Command("helm", "repo", "add", "falcosecurity", ts.cfg.ChartRepo)
+	addRepo.Stdout, addRepo.Stderr = ts.cfg.LogWriter, ts.cfg.LogWriter
+	if err := addRepo.Run(); err != nil {
+		return err
+	}
+
+	install := exec.Command("helm", "upgrade", "--install", ts.cfg.ReleaseName, ts.cfg.ChartName,
+		"--namespace", ts.cfg.Namespace,
+		"--create-namespace",
+		"--set", "falcosidekick.enabled=true",
+		"--wait",
+	)
+	install.Stdout, install.Stderr = ts.cfg.LogWriter, ts.cfg.LogWriter
+	return install.Run()
+}
+
+// attackPods is the curated set of workloads used to trigger Falco's
+// default rule set.
+var attackPods = []struct {
+	name string
+	args []string
+}{
+	{"falco-attack-privileged-shell", []string{"run", "falco-attack-privileged-shell", "--image=busybox", "--restart=Never", "--privileged", "--", "sh", "-c", "sleep 60"}},
+	{"falco-attack-write-etc", []string{"run", "falco-attack-write-etc", "--image=busybox", "--restart=Never", "--", "sh", "-c", "echo pwned >> /etc/passwd; sleep 60"}},
+	{"falco-attack-package-manager", []string{"run", "falco-attack-package-manager", "--image=ubuntu", "--restart=Never", "--", "sh", "-c", "apt-get update; sleep 60"}},
+	{"falco-attack-host-mount", []string{"run", "falco-attack-host-mount", "--image=busybox", "--restart=Never", "--overrides", hostMountOverrides, "--", "sh", "-c", "sleep 60"}},
+}
+
+const hostMountOverrides = `{"spec":{"containers":[{"name":"falco-attack-host-mount","image":"busybox","command":["sh","-c","sleep 60"],"volumeMounts":[{"name":"host-root","mountPath":"/host"}]}],"volumes":[{"name":"host-root","hostPath":{"path":"/etc"}}]}}`
+
+func (ts *tester) deployAttackPods() error {
+	for _, p := range attackPods {
+		args := append([]string{"--namespace", ts.cfg.Namespace}, p.args...)
+		if labels := ts.managedByLabels(); labels != "" {
+			args = insertBeforeDashDash(args, "--labels", labels)
+		}
+		cmd := exec.Command("kubectl", ts.kubectlArgs(args...)...)
+		cmd.Stdout, cmd.Stderr = ts.cfg.LogWriter, ts.cfg.LogWriter
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run %q: %w", p.name, err)
+		}
+	}
+	// exec into the privileged-shell pod so the "Terminal shell in container" rule fires.
+	shell := exec.Command("kubectl", ts.kubectlArgs("--namespace", ts.cfg.Namespace, "exec", "falco-attack-privileged-shell", "--", "sh", "-c", "true")...)
+	shell.Stdout, shell.Stderr = ts.cfg.LogWriter, ts.cfg.LogWriter
+	return shell.Run()
+}
+
+func (ts *tester) deleteAttackPods() error {
+	var errs []string
+	for _, p := range attackPods {
+		cmd := exec.Command("kubectl", ts.kubectlArgs("--namespace", ts.cfg.Namespace, "delete", "pod", p.name, "--ignore-not-found")...)
+		cmd.Stdout, cmd.Stderr = ts.cfg.LogWriter, ts.cfg.LogWriter
+		if err := cmd.Run(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// managedByLabels renders ts.cfg.Tester.ManagedByLabels() as a
+// comma-separated "k=v" list suitable for "kubectl run --labels", or "" if
+// Tester isn't configured.
+func (ts *tester) managedByLabels() string {
+	if ts.cfg.Tester == nil {
+		return ""
+	}
+	labels := ts.cfg.Tester.ManagedByLabels()
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// insertBeforeDashDash inserts extra into args immediately before args'
+// first "--" separator (or appends extra if there is none), so flags like
+// "--labels" land before "kubectl run"'s container-command separator
+// instead of after it, where they'd be passed to the container command
+// instead of to kubectl.
+func insertBeforeDashDash(args []string, extra ...string) []string {
+	for i, a := range args {
+		if a == "--" {
+			out := make([]string, 0, len(args)+len(extra))
+			out = append(out, args[:i]...)
+			out = append(out, extra...)
+			out = append(out, args[i:]...)
+			return out
+		}
+	}
+	return append(args, extra...)
+}
+
+// kubectlArgs prepends "--kubeconfig" (from ts.cfg.Client) and, if
+// KubeconfigContext is set, "--context" to extra, so every kubectl
+// invocation this tester makes targets the same cluster/context as the rest
+// of the run instead of silently falling back to the ambient kubeconfig.
+func (ts *tester) kubectlArgs(extra ...string) []string {
+	args := []string{"--kubeconfig", ts.cfg.Client.KubeconfigPath()}
+	if ts.cfg.KubeconfigContext != "" {
+		args = append(args, "--context", ts.cfg.KubeconfigContext)
+	}
+	return append(args, extra...)
+}
+
+// waitForRules reads the logs of the Falco pods (label
+// app.kubernetes.io/name=falco) until every rule name in rules has been
+// observed, or timeout elapses.
+func (ts *tester) waitForRules(rules []string, timeout time.Duration) (map[string]bool, error) {
+	clientset := ts.cfg.Client.KubernetesClientSet()
+	want := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		want[r] = false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+		pods, err := clientset.CoreV1().Pods(ts.cfg.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "app.kubernetes.io/name=falco",
+		})
+		if err != nil {
+			return false, nil
+		}
+		for _, pod := range pods.Items {
+			req := clientset.CoreV1().Pods(ts.cfg.Namespace).GetLogs(pod.Name, &corev1LogOptions)
+			stream, err := req.Stream(ctx)
+			if err != nil {
+				continue
+			}
+			buf := new(bytes.Buffer)
+			_, _ = buf.ReadFrom(stream)
+			_ = stream.Close()
+
+			for _, rule := range recordFiredRules(buf.String(), want) {
+				ts.cfg.Logger.Info("observed falco rule", zap.String("rule", rule))
+				if ts.cfg.MetricsSink != nil {
+					ts.cfg.MetricsSink.Record("falco_rule_fired", map[string]string{"rule": rule}, 1, time.Now())
+				}
+				if ts.cfg.Events != nil {
+					if eerr := ts.cfg.Events.EmitType(ctx, "falco", "rule.fired", map[string]string{"rule": rule}); eerr != nil {
+						ts.cfg.Logger.Warn("failed to emit falco.rule.fired event", zap.Error(eerr))
+					}
+				}
+			}
+		}
+		firedSoFar, allFired := firedRuleNames(want)
+		ts.emit(addonevent.EventWaitProgress, time.Since(start), "", firedSoFar...)
+		return allFired, nil
+	}, ctx.Done())
+
+	if ts.cfg.MetricsSink != nil {
+		if ferr := ts.cfg.MetricsSink.Flush(ctx); ferr != nil {
+			ts.cfg.Logger.Warn("failed to flush falco metrics to AMP", zap.Error(ferr))
+		}
+	}
+
+	if err != nil && !errors.Is(err, wait.ErrWaitTimeout) {
+		return want, err
+	}
+	return want, nil
+}
+
+// recordFiredRules marks every not-yet-fired rule in want whose name occurs
+// in logs as fired, and returns the names newly marked this call (not every
+// rule fired so far).
+func recordFiredRules(logs string, want map[string]bool) []string {
+	var newlyFired []string
+	for rule := range want {
+		if !want[rule] && strings.Contains(logs, rule) {
+			want[rule] = true
+			newlyFired = append(newlyFired, rule)
+		}
+	}
+	sort.Strings(newlyFired)
+	return newlyFired
+}
+
+// firedRuleNames returns the sorted names of every rule marked fired in
+// want, plus whether every rule in want has fired.
+func firedRuleNames(want map[string]bool) (fired []string, allFired bool) {
+	allFired = true
+	for rule, ok := range want {
+		if ok {
+			fired = append(fired, rule)
+		} else {
+			allFired = false
+		}
+	}
+	sort.Strings(fired)
+	return fired, allFired
+}
@@ -0,0 +1,146 @@
+package falco
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-k8s-tester/client"
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonevent"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// fakeClient embeds a nil client.Client so it only needs to override the
+// method(s) a given test actually exercises, rather than implementing every
+// method on the real interface.
+type fakeClient struct {
+	client.Client
+	kubeconfigPath string
+}
+
+func (f fakeClient) KubeconfigPath() string { return f.kubeconfigPath }
+
+func TestInsertBeforeDashDash(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		extra []string
+		want  []string
+	}{
+		{
+			name:  "inserts before separator",
+			args:  []string{"run", "pod", "--image=busybox", "--", "sh", "-c", "sleep 60"},
+			extra: []string{"--labels", "a=b"},
+			want:  []string{"run", "pod", "--image=busybox", "--labels", "a=b", "--", "sh", "-c", "sleep 60"},
+		},
+		{
+			name:  "appends when no separator",
+			args:  []string{"delete", "pod", "my-pod"},
+			extra: []string{"--ignore-not-found"},
+			want:  []string{"delete", "pod", "my-pod", "--ignore-not-found"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := insertBeforeDashDash(tc.args, tc.extra...)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("insertBeforeDashDash(%v, %v) = %v, want %v", tc.args, tc.extra, got, tc.want)
+			}
+		})
+	}
+}
+
+type fakeTester struct {
+	managedBy string
+	labels    map[string]string
+}
+
+func (f fakeTester) EmitEvent(_ *zap.Logger, _ addonevent.Event) error { return nil }
+func (f fakeTester) NamespaceFor(_ string, override string) string     { return override }
+func (f fakeTester) EnsureNamespace(context.Context, kubernetes.Interface, string) error {
+	return nil
+}
+func (f fakeTester) DeleteNamespace(context.Context, kubernetes.Interface, string) error {
+	return nil
+}
+func (f fakeTester) ManagedByLabels() map[string]string { return f.labels }
+func (f fakeTester) GetManagedBy() string               { return f.managedBy }
+
+func TestManagedByLabelsSortsAndJoins(t *testing.T) {
+	ts := &tester{cfg: &Config{Tester: fakeTester{labels: map[string]string{
+		"app.kubernetes.io/managed-by": "aws-k8s-tester",
+		"k8s-tester/cluster-name":      "my-cluster",
+	}}}}
+
+	got := ts.managedByLabels()
+	want := "app.kubernetes.io/managed-by=aws-k8s-tester,k8s-tester/cluster-name=my-cluster"
+	if got != want {
+		t.Errorf("managedByLabels() = %q, want %q", got, want)
+	}
+}
+
+func TestManagedByLabelsNilTester(t *testing.T) {
+	ts := &tester{cfg: &Config{}}
+	if got := ts.managedByLabels(); got != "" {
+		t.Errorf("managedByLabels() with nil Tester = %q, want empty", got)
+	}
+}
+
+func TestKubectlArgsAddsContextOnlyWhenSet(t *testing.T) {
+	ts := &tester{cfg: &Config{Client: fakeClient{kubeconfigPath: "/tmp/kubeconfig"}}}
+	got := ts.kubectlArgs("get", "pods")
+	want := []string{"--kubeconfig", "/tmp/kubeconfig", "get", "pods"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kubectlArgs() = %v, want %v", got, want)
+	}
+
+	ts.cfg.KubeconfigContext = "cluster-b"
+	got = ts.kubectlArgs("get", "pods")
+	want = []string{"--kubeconfig", "/tmp/kubeconfig", "--context", "cluster-b", "get", "pods"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kubectlArgs() with KubeconfigContext set = %v, want %v", got, want)
+	}
+}
+
+func TestRecordFiredRules(t *testing.T) {
+	want := map[string]bool{
+		"Terminal shell in container": false,
+		"Write below etc":             false,
+	}
+
+	fired := recordFiredRules("some log line mentioning Terminal shell in container happened", want)
+	if !reflect.DeepEqual(fired, []string{"Terminal shell in container"}) {
+		t.Errorf("first call returned %v, want [Terminal shell in container]", fired)
+	}
+	if !want["Terminal shell in container"] {
+		t.Error("want map was not updated for the fired rule")
+	}
+
+	// A rule already marked fired must not be reported again.
+	fired = recordFiredRules("Terminal shell in container logged again", want)
+	if len(fired) != 0 {
+		t.Errorf("already-fired rule reported again: %v", fired)
+	}
+}
+
+func TestFiredRuleNames(t *testing.T) {
+	want := map[string]bool{"a": true, "b": false, "c": true}
+
+	fired, allFired := firedRuleNames(want)
+	if allFired {
+		t.Error("allFired = true, want false since \"b\" hasn't fired")
+	}
+	if !reflect.DeepEqual(fired, []string{"a", "c"}) {
+		t.Errorf("fired = %v, want [a c]", fired)
+	}
+
+	want["b"] = true
+	fired, allFired = firedRuleNames(want)
+	if !allFired {
+		t.Error("allFired = false, want true once every rule has fired")
+	}
+	if !reflect.DeepEqual(fired, []string{"a", "b", "c"}) {
+		t.Errorf("fired = %v, want [a b c]", fired)
+	}
+}
@@ -0,0 +1,42 @@
+package falco
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonlister"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func init() {
+	addonlister.Register("falco", lister{})
+}
+
+type lister struct{}
+
+// List implements addonlister.Lister (aliased as k8s_tester.Lister),
+// surfacing the pods labeled app.kubernetes.io/name=falco. The falcosidekick
+// pods and the attack pods Apply deploys do not carry that label and are not
+// listed.
+func (lister) List(ctx context.Context, clientset kubernetes.Interface) ([]addonlister.Row, error) {
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/name=falco",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list falco pods: %w", err)
+	}
+
+	rows := make([]addonlister.Row, 0, len(pods.Items))
+	for _, p := range pods.Items {
+		rows = append(rows, addonlister.Row{
+			Name:      p.Name,
+			Namespace: p.Namespace,
+			Status:    string(p.Status.Phase),
+			Age:       time.Since(p.CreationTimestamp.Time).Round(time.Second).String(),
+			Addon:     "falco",
+		})
+	}
+	return rows, nil
+}
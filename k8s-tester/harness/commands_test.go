@@ -0,0 +1,45 @@
+package harness
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPluralize(t *testing.T) {
+	cases := map[string]string{
+		"Pod":           "pods",
+		"Service":       "services",
+		"Ingress":       "ingresses",
+		"ConfigMap":     "configmaps",
+		"Deployment":    "deployments",
+		"NetworkPolicy": "networkpolicies",
+	}
+	for kind, want := range cases {
+		if got := pluralize(kind); got != want {
+			t.Errorf("pluralize(%q) = %q, want %q", kind, got, want)
+		}
+	}
+}
+
+func TestGVRFor(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+	}}
+	gvr := gvrFor(obj)
+	if gvr.Group != "apps" || gvr.Version != "v1" || gvr.Resource != "deployments" {
+		t.Errorf("got %+v", gvr)
+	}
+}
+
+func TestGVRForCoreGroup(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+	}}
+	gvr := gvrFor(obj)
+	if gvr.Group != "" || gvr.Version != "v1" || gvr.Resource != "pods" {
+		t.Errorf("got %+v", gvr)
+	}
+}
@@ -0,0 +1,283 @@
+// Package harness is a declarative, YAML-driven test-step runner, modeled
+// on the KUDO test harness: point it at a directory tree of numbered step
+// files (00-apply.yaml / 00-assert.yaml / 00-errors.yaml, 01-..., ...) and
+// it applies each step's manifests, then polls the live cluster until the
+// assertion objects match (or an error object appears), so users can write
+// conformance-style suites against the addons in this repo without
+// authoring Go.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/client"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/yaml"
+)
+
+// Config defines harness configuration.
+type Config struct {
+	Enable bool `json:"enable"`
+
+	Logger    *zap.Logger `json:"-"`
+	LogWriter io.Writer   `json:"-"`
+
+	Client client.Client `json:"-"`
+
+	// Dir is the root directory containing the numbered step files.
+	Dir string `json:"dir"`
+	// StepTimeout bounds how long each step's assert phase polls before
+	// failing.
+	StepTimeout time.Duration `json:"step_timeout"`
+	// PollInterval is how often the assert phase re-checks object state.
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+// NewDefault returns a default Config.
+func NewDefault() *Config {
+	return &Config{
+		StepTimeout:  2 * time.Minute,
+		PollInterval: 2 * time.Second,
+	}
+}
+
+// StepResult is the outcome of running a single numbered step.
+type StepResult struct {
+	Name    string        `json:"name"`
+	Stdout  string        `json:"stdout"`
+	Diffs   string        `json:"diffs,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+	Err     error         `json:"-"`
+}
+
+// Runner executes a harness test suite.
+type Runner struct {
+	cfg *Config
+}
+
+// NewRunner creates a Runner.
+func NewRunner(cfg *Config) *Runner {
+	return &Runner{cfg: cfg}
+}
+
+var stepFileRE = regexp.MustCompile(`^(\d+)-(apply|assert|errors|commands)\.yaml$`)
+
+type step struct {
+	seq      string
+	apply    string
+	assert   string
+	errors   string
+	commands string
+}
+
+// Run discovers the numbered steps under dir and executes them in order,
+// returning one StepResult per step. It stops at the first failing step.
+func (r *Runner) Run(ctx context.Context, dir string) ([]StepResult, error) {
+	steps, err := discoverSteps(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover harness steps in %q: %w", dir, err)
+	}
+
+	var results []StepResult
+	for _, s := range steps {
+		start := time.Now()
+		res := r.runStep(ctx, dir, s)
+		res.Elapsed = time.Since(start)
+		results = append(results, res)
+		if res.Err != nil {
+			return results, fmt.Errorf("harness step %q failed: %w", s.seq, res.Err)
+		}
+	}
+	return results, nil
+}
+
+func discoverSteps(dir string) ([]step, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bySeq := map[string]*step{}
+	for _, e := range entries {
+		m := stepFileRE.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		seq, kind := m[1], m[2]
+		s, ok := bySeq[seq]
+		if !ok {
+			s = &step{seq: seq}
+			bySeq[seq] = s
+		}
+		p := filepath.Join(dir, e.Name())
+		switch kind {
+		case "apply":
+			s.apply = p
+		case "assert":
+			s.assert = p
+		case "errors":
+			s.errors = p
+		case "commands":
+			s.commands = p
+		}
+	}
+
+	seqs := make([]string, 0, len(bySeq))
+	for seq := range bySeq {
+		seqs = append(seqs, seq)
+	}
+	sort.Strings(seqs)
+
+	steps := make([]step, 0, len(seqs))
+	for _, seq := range seqs {
+		steps = append(steps, *bySeq[seq])
+	}
+	return steps, nil
+}
+
+func (r *Runner) runStep(ctx context.Context, dir string, s step) StepResult {
+	res := StepResult{Name: s.seq}
+
+	stepCfg, err := loadStepCommands(s.commands)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to load commands file for step %q: %w", s.seq, err)
+		return res
+	}
+
+	namespace := ""
+	if stepCfg.Namespace {
+		namespace = fmt.Sprintf("k8s-tester-harness-%s-%s", s.seq, randomSuffix())
+		if err := r.createNamespace(ctx, namespace); err != nil {
+			res.Err = fmt.Errorf("failed to create namespace %q for step %q: %w", namespace, s.seq, err)
+			return res
+		}
+		defer r.deleteNamespace(ctx, namespace)
+	}
+
+	for _, c := range stepCfg.Commands {
+		out, err := r.runCommand(ctx, c, namespace)
+		res.Stdout += out
+		if err != nil {
+			res.Err = fmt.Errorf("command failed in step %q: %w", s.seq, err)
+			return res
+		}
+	}
+
+	if s.apply != "" {
+		if out, err := r.kubectlApply(ctx, s.apply, namespace); err != nil {
+			res.Stdout += out
+			res.Err = fmt.Errorf("failed to apply %q: %w", s.apply, err)
+			return res
+		} else {
+			res.Stdout += out
+		}
+	}
+
+	if s.assert != "" || s.errors != "" {
+		diffs, err := r.waitForAssertion(ctx, s, namespace)
+		res.Diffs = diffs
+		if err != nil {
+			res.Err = err
+			return res
+		}
+	}
+
+	for _, obj := range stepCfg.Delete {
+		if err := r.deleteObject(ctx, obj, namespace); err != nil {
+			res.Err = fmt.Errorf("failed to delete object in step %q: %w", s.seq, err)
+			return res
+		}
+	}
+
+	return res
+}
+
+// waitForAssertion polls the cluster until every object in the assert file
+// subset-matches live state, or any object matching the errors file is
+// observed, or StepTimeout elapses.
+func (r *Runner) waitForAssertion(ctx context.Context, s step, namespace string) (string, error) {
+	var asserts, errs []*unstructured.Unstructured
+	var err error
+	if s.assert != "" {
+		if asserts, err = loadObjects(s.assert); err != nil {
+			return "", fmt.Errorf("failed to load %q: %w", s.assert, err)
+		}
+	}
+	if s.errors != "" {
+		if errs, err = loadObjects(s.errors); err != nil {
+			return "", fmt.Errorf("failed to load %q: %w", s.errors, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.StepTimeout)
+	defer cancel()
+
+	var lastDiff string
+	pollErr := wait.PollImmediateUntil(r.cfg.PollInterval, func() (bool, error) {
+		for _, want := range errs {
+			live, ferr := r.getObject(ctx, want, namespace)
+			if ferr == nil && live != nil && subsetDiff(want.Object, live.Object) == "" {
+				return false, fmt.Errorf("observed object matching errors.yaml: %s/%s", want.GetKind(), want.GetName())
+			}
+		}
+
+		for _, want := range asserts {
+			live, ferr := r.getObject(ctx, want, namespace)
+			if ferr != nil || live == nil {
+				lastDiff = fmt.Sprintf("%s/%s not found yet", want.GetKind(), want.GetName())
+				return false, nil
+			}
+			if diff := subsetDiff(want.Object, live.Object); diff != "" {
+				lastDiff = diff
+				return false, nil
+			}
+		}
+		return true, nil
+	}, ctx.Done())
+
+	if pollErr != nil {
+		if pollErr == wait.ErrWaitTimeout {
+			return lastDiff, fmt.Errorf("assertion for step %q did not converge within %s: %s", s.seq, r.cfg.StepTimeout, lastDiff)
+		}
+		return lastDiff, pollErr
+	}
+	return "", nil
+}
+
+// yamlDocSeparatorRE matches a "---" document separator line, the same way
+// kubectl/kuttl split a multi-document assert/errors file into one object
+// per document.
+var yamlDocSeparatorRE = regexp.MustCompile(`(?m)^---\s*$`)
+
+func loadObjects(path string) ([]*unstructured.Unstructured, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, doc := range yamlDocSeparatorRE.Split(string(raw), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse document in %q: %w", path, err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: obj})
+	}
+	return objs, nil
+}
@@ -0,0 +1,56 @@
+package harness
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadObjectsMultiDocument(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "assert.yaml")
+	content := `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-a
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-b
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	objs, err := loadObjects(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("got %d objects, want 2", len(objs))
+	}
+	if objs[0].GetName() != "pod-a" || objs[1].GetName() != "pod-b" {
+		t.Errorf("got names %q, %q", objs[0].GetName(), objs[1].GetName())
+	}
+}
+
+func TestLoadObjectsSingleDocument(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "assert.yaml")
+	content := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod-a\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	objs, err := loadObjects(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objs) != 1 {
+		t.Fatalf("got %d objects, want 1", len(objs))
+	}
+	if objs[0].GetName() != "pod-a" {
+		t.Errorf("got name %q", objs[0].GetName())
+	}
+}
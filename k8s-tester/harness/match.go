@@ -0,0 +1,33 @@
+package harness
+
+import "fmt"
+
+// subsetDiff reports whether every field in want is present in live with an
+// equal value, recursing into nested maps. It returns an empty string on a
+// match, or a human-readable description of the first mismatch.
+func subsetDiff(want, live map[string]interface{}) string {
+	for k, wv := range want {
+		lv, ok := live[k]
+		if !ok {
+			return fmt.Sprintf("missing field %q", k)
+		}
+
+		wm, wIsMap := wv.(map[string]interface{})
+		lm, lIsMap := lv.(map[string]interface{})
+		if wIsMap && lIsMap {
+			if diff := subsetDiff(wm, lm); diff != "" {
+				return fmt.Sprintf("%s.%s", k, diff)
+			}
+			continue
+		}
+
+		if !deepEqual(wv, lv) {
+			return fmt.Sprintf("field %q: want %v, got %v", k, wv, lv)
+		}
+	}
+	return ""
+}
+
+func deepEqual(a, b interface{}) bool {
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
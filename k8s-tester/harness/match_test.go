@@ -0,0 +1,46 @@
+package harness
+
+import "testing"
+
+func TestSubsetDiffMatch(t *testing.T) {
+	want := map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": "Running",
+		},
+	}
+	live := map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":     "Running",
+			"startTime": "2026-01-01T00:00:00Z",
+		},
+		"metadata": map[string]interface{}{"name": "pod-a"},
+	}
+	if diff := subsetDiff(want, live); diff != "" {
+		t.Errorf("expected no diff, got %q", diff)
+	}
+}
+
+func TestSubsetDiffMissingField(t *testing.T) {
+	want := map[string]interface{}{"status": map[string]interface{}{"phase": "Running"}}
+	live := map[string]interface{}{"metadata": map[string]interface{}{"name": "pod-a"}}
+	if diff := subsetDiff(want, live); diff != `missing field "status"` {
+		t.Errorf("got %q", diff)
+	}
+}
+
+func TestSubsetDiffFieldMismatch(t *testing.T) {
+	want := map[string]interface{}{"status": map[string]interface{}{"phase": "Running"}}
+	live := map[string]interface{}{"status": map[string]interface{}{"phase": "Pending"}}
+	if diff := subsetDiff(want, live); diff != `status.field "phase": want Running, got Pending` {
+		t.Errorf("got %q", diff)
+	}
+}
+
+func TestDeepEqual(t *testing.T) {
+	if !deepEqual(1, 1) {
+		t.Error("expected 1 == 1")
+	}
+	if deepEqual("a", "b") {
+		t.Error("expected a != b")
+	}
+}
@@ -0,0 +1,83 @@
+package k8s_tester
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// MergeKubeconfigs merges every file in KubeconfigPaths into a single
+// kubeconfig written to KubeconfigPath, renaming clusters/users/contexts
+// whose names conflict (suffixing with the source file's index) so a single
+// tester run can target multiple clusters. Same-named entries are never
+// collapsed into one: both are kept, even when identical. E.g. running
+// nlb-hello-world on cluster A while fluent-bit installs on cluster B.
+func (cfg *Config) MergeKubeconfigs() error {
+	if len(cfg.KubeconfigPaths) == 0 {
+		return fmt.Errorf("empty 'KubeconfigPaths'")
+	}
+	if cfg.KubeconfigPath == "" {
+		return fmt.Errorf("empty 'KubeconfigPath' to merge into")
+	}
+
+	merged := clientcmdapi.NewConfig()
+	for i, p := range cfg.KubeconfigPaths {
+		raw, err := clientcmd.LoadFromFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to load kubeconfig %q: %w", p, err)
+		}
+
+		clusterNames := map[string]string{}  // source name -> merged name
+		authInfoNames := map[string]string{} // source name -> merged name
+
+		for name, cluster := range raw.Clusters {
+			newName := renameOnClusterConflict(merged, name, i)
+			merged.Clusters[newName] = cluster
+			clusterNames[name] = newName
+		}
+		for name, authInfo := range raw.AuthInfos {
+			newName := renameOnAuthInfoConflict(merged, name, i)
+			merged.AuthInfos[newName] = authInfo
+			authInfoNames[name] = newName
+		}
+		for name, kctx := range raw.Contexts {
+			newName := renameOnContextConflict(merged, name, i)
+			kctx.Cluster = clusterNames[kctx.Cluster]
+			kctx.AuthInfo = authInfoNames[kctx.AuthInfo]
+			merged.Contexts[newName] = kctx
+			if raw.CurrentContext == name && merged.CurrentContext == "" {
+				merged.CurrentContext = newName
+			}
+		}
+	}
+
+	return clientcmd.WriteToFile(*merged, cfg.KubeconfigPath)
+}
+
+// renameOnClusterConflict, renameOnAuthInfoConflict, and
+// renameOnContextConflict return name unchanged unless merged already has an
+// entry under that name, in which case they suffix it with the source
+// kubeconfig's index so merging two kubeconfigs that both define e.g.
+// "my-cluster" doesn't clobber one with the other.
+
+func renameOnClusterConflict(merged *clientcmdapi.Config, name string, sourceIndex int) string {
+	if _, ok := merged.Clusters[name]; !ok {
+		return name
+	}
+	return fmt.Sprintf("%s-%d", name, sourceIndex)
+}
+
+func renameOnAuthInfoConflict(merged *clientcmdapi.Config, name string, sourceIndex int) string {
+	if _, ok := merged.AuthInfos[name]; !ok {
+		return name
+	}
+	return fmt.Sprintf("%s-%d", name, sourceIndex)
+}
+
+func renameOnContextConflict(merged *clientcmdapi.Config, name string, sourceIndex int) string {
+	if _, ok := merged.Contexts[name]; !ok {
+		return name
+	}
+	return fmt.Sprintf("%s-%d", name, sourceIndex)
+}
@@ -0,0 +1,121 @@
+package k8s_tester
+
+import (
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRenameOnClusterConflict(t *testing.T) {
+	merged := clientcmdapi.NewConfig()
+	merged.Clusters["my-cluster"] = &clientcmdapi.Cluster{}
+
+	if got := renameOnClusterConflict(merged, "other-cluster", 1); got != "other-cluster" {
+		t.Errorf("no conflict: got %q, want unchanged name", got)
+	}
+	if got := renameOnClusterConflict(merged, "my-cluster", 1); got != "my-cluster-1" {
+		t.Errorf("conflict: got %q, want %q", got, "my-cluster-1")
+	}
+}
+
+func TestRenameOnAuthInfoConflict(t *testing.T) {
+	merged := clientcmdapi.NewConfig()
+	merged.AuthInfos["my-user"] = &clientcmdapi.AuthInfo{}
+
+	if got := renameOnAuthInfoConflict(merged, "other-user", 2); got != "other-user" {
+		t.Errorf("no conflict: got %q, want unchanged name", got)
+	}
+	if got := renameOnAuthInfoConflict(merged, "my-user", 2); got != "my-user-2" {
+		t.Errorf("conflict: got %q, want %q", got, "my-user-2")
+	}
+}
+
+func TestRenameOnContextConflict(t *testing.T) {
+	merged := clientcmdapi.NewConfig()
+	merged.Contexts["my-context"] = &clientcmdapi.Context{}
+
+	if got := renameOnContextConflict(merged, "other-context", 3); got != "other-context" {
+		t.Errorf("no conflict: got %q, want unchanged name", got)
+	}
+	if got := renameOnContextConflict(merged, "my-context", 3); got != "my-context-3" {
+		t.Errorf("conflict: got %q, want %q", got, "my-context-3")
+	}
+}
+
+func TestMergeKubeconfigsRequiresPaths(t *testing.T) {
+	cfg := &Config{KubeconfigPath: "/tmp/merged"}
+	if err := cfg.MergeKubeconfigs(); err == nil {
+		t.Fatal("expected error for empty KubeconfigPaths, got nil")
+	}
+}
+
+func TestMergeKubeconfigsRequiresOutputPath(t *testing.T) {
+	cfg := &Config{KubeconfigPaths: []string{"/tmp/a"}}
+	if err := cfg.MergeKubeconfigs(); err == nil {
+		t.Fatal("expected error for empty KubeconfigPath, got nil")
+	}
+}
+
+func TestMergeKubeconfigsEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+
+	// Both fixtures define a cluster/context named "my-cluster", so merging
+	// must rename the second one's cluster/context (suffixed "-1") rather
+	// than clobbering the first.
+	a := clientcmdapi.NewConfig()
+	a.Clusters["my-cluster"] = &clientcmdapi.Cluster{Server: "https://a.example.com"}
+	a.AuthInfos["my-user"] = &clientcmdapi.AuthInfo{Token: "token-a"}
+	a.Contexts["my-cluster"] = &clientcmdapi.Context{Cluster: "my-cluster", AuthInfo: "my-user"}
+	a.CurrentContext = "my-cluster"
+
+	b := clientcmdapi.NewConfig()
+	b.Clusters["my-cluster"] = &clientcmdapi.Cluster{Server: "https://b.example.com"}
+	b.AuthInfos["my-user"] = &clientcmdapi.AuthInfo{Token: "token-b"}
+	b.Contexts["my-cluster"] = &clientcmdapi.Context{Cluster: "my-cluster", AuthInfo: "my-user"}
+	b.CurrentContext = "my-cluster"
+
+	pathA := filepath.Join(dir, "a.kubeconfig")
+	pathB := filepath.Join(dir, "b.kubeconfig")
+	if err := clientcmd.WriteToFile(*a, pathA); err != nil {
+		t.Fatalf("failed to write fixture %q: %v", pathA, err)
+	}
+	if err := clientcmd.WriteToFile(*b, pathB); err != nil {
+		t.Fatalf("failed to write fixture %q: %v", pathB, err)
+	}
+
+	mergedPath := filepath.Join(dir, "merged.kubeconfig")
+	cfg := &Config{KubeconfigPaths: []string{pathA, pathB}, KubeconfigPath: mergedPath}
+	if err := cfg.MergeKubeconfigs(); err != nil {
+		t.Fatalf("MergeKubeconfigs failed: %v", err)
+	}
+
+	merged, err := clientcmd.LoadFromFile(mergedPath)
+	if err != nil {
+		t.Fatalf("failed to load merged kubeconfig %q: %v", mergedPath, err)
+	}
+
+	if _, ok := merged.Clusters["my-cluster"]; !ok {
+		t.Error(`merged config missing unconflicted cluster "my-cluster"`)
+	}
+	renamed, ok := merged.Clusters["my-cluster-1"]
+	if !ok {
+		t.Fatal(`merged config missing renamed conflicting cluster "my-cluster-1"`)
+	}
+	if renamed.Server != "https://b.example.com" {
+		t.Errorf("renamed cluster server = %q, want %q", renamed.Server, "https://b.example.com")
+	}
+
+	renamedCtx, ok := merged.Contexts["my-cluster-1"]
+	if !ok {
+		t.Fatal(`merged config missing renamed conflicting context "my-cluster-1"`)
+	}
+	if renamedCtx.Cluster != "my-cluster-1" || renamedCtx.AuthInfo != "my-user-1" {
+		t.Errorf("renamed context = %+v, want Cluster=my-cluster-1 AuthInfo=my-user-1", renamedCtx)
+	}
+
+	if merged.CurrentContext != "my-cluster" {
+		t.Errorf("CurrentContext = %q, want %q (first source wins)", merged.CurrentContext, "my-cluster")
+	}
+}
@@ -0,0 +1,36 @@
+package k8s_tester
+
+import (
+	"github.com/aws/aws-k8s-tester/k8s-tester/addonlister"
+)
+
+// Row is one line of a `k8s-tester list`/`describe` inventory table. It's a
+// type alias for addonlister.Row (rather than a local struct) so that
+// addons which can't import this package back without a cycle -- e.g.
+// falco, whose Config is embedded here as AddOnFalco -- can still implement
+// Lister against the exact same type via addonlister.
+type Row = addonlister.Row
+
+// Lister is implemented by each addon that wants to surface the resources
+// it owns (pods, jobs, services, NLB endpoints, deployments, ...) to the
+// `k8s-tester list`/`describe` CLI, instead of operators having to remember
+// each addon's namespaces/label selectors.
+type Lister = addonlister.Lister
+
+// RegisterLister registers l under addon, the same name used by the
+// `list`/`describe` CLI's `<addon>` argument. Addon packages call this from
+// an init() func the same way they register their Config with NewDefault.
+func RegisterLister(addon string, l Lister) {
+	addonlister.Register(addon, l)
+}
+
+// ListerFor returns the registered Lister for addon, if any.
+func ListerFor(addon string) (Lister, bool) {
+	return addonlister.For(addon)
+}
+
+// ListerNames returns the names of every registered Lister, for CLI help
+// text and validation.
+func ListerNames() []string {
+	return addonlister.Names()
+}
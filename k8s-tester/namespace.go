@@ -0,0 +1,54 @@
+package k8s_tester
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NamespaceFor returns the namespace an addon should run in: its own
+// explicit override if non-empty, otherwise a name auto-generated from
+// NamespacePrefix so concurrent tester runs on the same cluster don't
+// collide (e.g. "k8s-tester-<run-id>-jobs-pi").
+//
+// Addon Config structs that want per-addon isolation call this with their
+// own "Namespace" field as override; when that field is blank they get a
+// unique namespace for free.
+func (cfg *Config) NamespaceFor(addon string, override string) string {
+	if override != "" {
+		return override
+	}
+	return fmt.Sprintf("%s-%s", cfg.NamespacePrefix, addon)
+}
+
+// EnsureNamespace idempotently creates namespace, tagging it with
+// ManagedByLabels plus a "k8s-tester/cluster-name" annotation so operators
+// can tell which run a namespace belongs to.
+func (cfg *Config) EnsureNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	_, err := clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: cfg.ManagedByLabels(),
+			Annotations: map[string]string{
+				"k8s-tester/cluster-name": cfg.ClusterName,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %q: %w", namespace, err)
+	}
+	return nil
+}
+
+// DeleteNamespace garbage-collects a namespace created by EnsureNamespace.
+func (cfg *Config) DeleteNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	err := clientset.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete namespace %q: %w", namespace, err)
+	}
+	return nil
+}
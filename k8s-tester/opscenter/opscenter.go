@@ -0,0 +1,140 @@
+// Package opscenter opens an AWS Systems Manager OpsCenter OpsItem whenever
+// a k8s-tester Apply/Delete call fails, and lets callers gate retries/
+// teardown on a human walking the item through Open -> InProgress ->
+// Resolved, instead of operators having to scrape ginkgo output.
+package opscenter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"go.uber.org/zap"
+)
+
+const maxLogTailBytes = 4000
+
+// ssmClient is the subset of *ssm.Client that Reporter calls, so tests can
+// substitute a fake instead of making real SSM API calls.
+type ssmClient interface {
+	CreateOpsItem(ctx context.Context, params *ssm.CreateOpsItemInput, optFns ...func(*ssm.Options)) (*ssm.CreateOpsItemOutput, error)
+	GetOpsItem(ctx context.Context, params *ssm.GetOpsItemInput, optFns ...func(*ssm.Options)) (*ssm.GetOpsItemOutput, error)
+}
+
+// Config defines opscenter configuration.
+type Config struct {
+	Logger *zap.Logger `json:"-"`
+	Client ssmClient   `json:"-"`
+
+	// ClusterARN identifies the cluster in the OpsItem's OperationalData
+	// so operators can jump straight from OpsCenter to the cluster.
+	ClusterARN string `json:"cluster_arn"`
+	Region     string `json:"region"`
+
+	// ReconcilePollInterval is how often Reconcile re-checks OpsItem status.
+	ReconcilePollInterval time.Duration `json:"reconcile_poll_interval"`
+}
+
+// NewDefault returns a default Config.
+func NewDefault() *Config {
+	return &Config{
+		ReconcilePollInterval: 30 * time.Second,
+	}
+}
+
+// Reporter opens and tracks OpsItems for failing testers.
+type Reporter struct {
+	cfg *Config
+
+	mu        sync.Mutex
+	opsItemID map[string]string // tester name -> OpsItemId
+}
+
+// New creates a Reporter.
+func New(cfg *Config) *Reporter {
+	return &Reporter{
+		cfg:       cfg,
+		opsItemID: make(map[string]string),
+	}
+}
+
+// ReportFailure creates an OpsItem for a failing tester and records the
+// returned OpsItemId on the Reporter, keyed by tester name, for later
+// Reconcile calls.
+func (r *Reporter) ReportFailure(ctx context.Context, testerName string, cause error, logTail string) (string, error) {
+	if len(logTail) > maxLogTailBytes {
+		logTail = logTail[len(logTail)-maxLogTailBytes:]
+	}
+
+	out, err := r.cfg.Client.CreateOpsItem(ctx, &ssm.CreateOpsItemInput{
+		Title:       aws(fmt.Sprintf("k8s-tester: %s failed", testerName)),
+		Description: aws(cause.Error()),
+		Source:      aws("aws-k8s-tester"),
+		OperationalData: map[string]types.OpsItemDataValue{
+			"tester":     {Value: aws(testerName), Type: types.OpsItemDataTypeSearchableString},
+			"clusterArn": {Value: aws(r.cfg.ClusterARN), Type: types.OpsItemDataTypeSearchableString},
+			"region":     {Value: aws(r.cfg.Region), Type: types.OpsItemDataTypeSearchableString},
+			"logTail":    {Value: aws(logTail), Type: types.OpsItemDataTypeString},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create OpsItem for tester %q: %w", testerName, err)
+	}
+
+	id := *out.OpsItemId
+	r.mu.Lock()
+	r.opsItemID[testerName] = id
+	r.mu.Unlock()
+
+	r.cfg.Logger.Info("opened OpsItem for failing tester", zap.String("tester", testerName), zap.String("ops-item-id", id))
+	return id, nil
+}
+
+// Reconcile polls GetOpsItem for the status of the OpsItem opened for
+// testerName, and blocks until it transitions to Resolved or ctx is done.
+// Retries/teardown should call this before acting on a failed tester, so
+// the run is gated on human resolution rather than looping unattended.
+func (r *Reporter) Reconcile(ctx context.Context, testerName string) error {
+	r.mu.Lock()
+	id, ok := r.opsItemID[testerName]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no OpsItem recorded for tester %q", testerName)
+	}
+
+	ticker := time.NewTicker(r.cfg.ReconcilePollInterval)
+	defer ticker.Stop()
+
+	var lastStatus types.OpsItemStatus
+	for {
+		out, err := r.cfg.Client.GetOpsItem(ctx, &ssm.GetOpsItemInput{OpsItemId: &id})
+		if err != nil {
+			return fmt.Errorf("failed to get OpsItem %q: %w", id, err)
+		}
+
+		status := out.OpsItem.Status
+		if status != lastStatus {
+			r.cfg.Logger.Info("OpsItem status transitioned",
+				zap.String("tester", testerName),
+				zap.String("ops-item-id", id),
+				zap.String("status", string(status)),
+			)
+			lastStatus = status
+		}
+
+		if status == types.OpsItemStatusResolved {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func aws(s string) *string { return &s }
@@ -0,0 +1,84 @@
+package opscenter
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"go.uber.org/zap"
+)
+
+type fakeSSMClient struct {
+	createIn  *ssm.CreateOpsItemInput
+	createOut *ssm.CreateOpsItemOutput
+	createErr error
+
+	statuses []types.OpsItemStatus
+	getCalls int
+}
+
+func (f *fakeSSMClient) CreateOpsItem(_ context.Context, params *ssm.CreateOpsItemInput, _ ...func(*ssm.Options)) (*ssm.CreateOpsItemOutput, error) {
+	f.createIn = params
+	return f.createOut, f.createErr
+}
+
+func (f *fakeSSMClient) GetOpsItem(_ context.Context, params *ssm.GetOpsItemInput, _ ...func(*ssm.Options)) (*ssm.GetOpsItemOutput, error) {
+	status := f.statuses[f.getCalls]
+	if f.getCalls < len(f.statuses)-1 {
+		f.getCalls++
+	}
+	return &ssm.GetOpsItemOutput{OpsItem: &types.OpsItem{OpsItemId: params.OpsItemId, Status: status}}, nil
+}
+
+func TestReportFailureTruncatesLogTail(t *testing.T) {
+	id := "oi-123"
+	fake := &fakeSSMClient{createOut: &ssm.CreateOpsItemOutput{OpsItemId: &id}}
+	r := New(&Config{Logger: zap.NewNop(), Client: fake, ClusterARN: "arn:aws:eks:us-west-2:1:cluster/test"})
+
+	logTail := strings.Repeat("x", maxLogTailBytes+100)
+	got, err := r.ReportFailure(context.Background(), "falco", errTest{"chart install failed"}, logTail)
+	if err != nil {
+		t.Fatalf("ReportFailure returned error: %v", err)
+	}
+	if got != id {
+		t.Fatalf("ReportFailure returned id %q, want %q", got, id)
+	}
+	if n := len(*fake.createIn.OperationalData["logTail"].Value); n != maxLogTailBytes {
+		t.Errorf("logTail length = %d, want %d", n, maxLogTailBytes)
+	}
+
+	r.mu.Lock()
+	recorded := r.opsItemID["falco"]
+	r.mu.Unlock()
+	if recorded != id {
+		t.Fatalf("opsItemID[%q] = %q, want %q", "falco", recorded, id)
+	}
+}
+
+func TestReconcileWaitsForResolved(t *testing.T) {
+	fake := &fakeSSMClient{
+		statuses: []types.OpsItemStatus{types.OpsItemStatusOpen, types.OpsItemStatusInProgress, types.OpsItemStatusResolved},
+	}
+	r := New(&Config{Logger: zap.NewNop(), Client: fake, ReconcilePollInterval: time.Millisecond})
+	r.opsItemID["falco"] = "oi-123"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := r.Reconcile(ctx, "falco"); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+}
+
+func TestReconcileUnknownTester(t *testing.T) {
+	r := New(&Config{Logger: zap.NewNop(), Client: &fakeSSMClient{}})
+	if err := r.Reconcile(context.Background(), "falco"); err == nil {
+		t.Fatal("Reconcile with no recorded OpsItem should have errored")
+	}
+}
+
+type errTest struct{ msg string }
+
+func (e errTest) Error() string { return e.msg }